@@ -0,0 +1,60 @@
+// Command client is a minimal example of calling cart-service over gRPC
+// using the generated cartpb client, for consumers that want a typed
+// contract instead of the REST API.
+//
+// Usage:
+//
+//	go run ./cmd/client -addr localhost:9008 -token $JWT -user user123 -product prod1 -quantity 2
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/grpc/cartpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9008", "cart-service gRPC address")
+	token := flag.String("token", "", "bearer JWT to authenticate the call")
+	userID := flag.String("user", "", "user ID whose cart to operate on")
+	productID := flag.String("product", "", "product ID to add to the cart")
+	quantity := flag.Int("quantity", 1, "quantity to add")
+	timeout := flag.Duration("timeout", 5*time.Second, "per-call timeout")
+	flag.Parse()
+
+	dialCtx, cancelDial := context.WithTimeout(context.Background(), *timeout)
+	defer cancelDial()
+	conn, err := grpc.DialContext(dialCtx, *addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		log.Fatalf("failed to dial %s: %v", *addr, err)
+	}
+	defer conn.Close()
+
+	client := cartpb.NewCartServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if *token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+	}
+
+	cart, err := client.AddItem(ctx, &cartpb.AddItemRequest{
+		UserId:    *userID,
+		ProductId: *productID,
+		Quantity:  int32(*quantity),
+	})
+	if err != nil {
+		log.Fatalf("AddItem failed: %v", err)
+	}
+
+	log.Printf("cart for %s now has %d item(s) totalling %.2f", cart.Cart.UserId, cart.Cart.TotalItems, cart.Cart.TotalPrice)
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,15 +12,31 @@ import (
 	"time"
 
 	"github.com/aioutlet/cart-service/internal/config"
+	"github.com/aioutlet/cart-service/internal/events"
+	grpcserver "github.com/aioutlet/cart-service/internal/grpc"
 	"github.com/aioutlet/cart-service/internal/handlers"
 	"github.com/aioutlet/cart-service/internal/middleware"
+	"github.com/aioutlet/cart-service/internal/purge"
 	"github.com/aioutlet/cart-service/internal/repository"
 	"github.com/aioutlet/cart-service/internal/services"
+	"github.com/aioutlet/cart-service/internal/storage"
+	"github.com/aioutlet/cart-service/internal/storage/postgres"
+	"github.com/aioutlet/cart-service/internal/webhooks"
+	"github.com/aioutlet/cart-service/pkg/clients"
+	"github.com/aioutlet/cart-service/pkg/dynconfig"
+	"github.com/aioutlet/cart-service/pkg/jwks"
+	"github.com/aioutlet/cart-service/pkg/lock"
 	"github.com/aioutlet/cart-service/pkg/logger"
+	pkgredis "github.com/aioutlet/cart-service/pkg/redis"
+	"github.com/aioutlet/cart-service/pkg/saga"
 	"github.com/aioutlet/cart-service/pkg/secrets"
+	"github.com/aioutlet/cart-service/pkg/tracing"
 	dapr "github.com/dapr/go-sdk/client"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -48,31 +66,244 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Initialize logger
-	log := logger.New(cfg.Environment)
+	tracingCfg := tracing.TracingConfig{
+		ServiceName:    cfg.Tracing.ServiceName,
+		ServiceVersion: cfg.Tracing.ServiceVersion,
+		Environment:    cfg.Environment,
+		ExporterType:   tracing.ExporterType(cfg.Tracing.ExporterType),
+		Endpoint:       cfg.Tracing.Endpoint,
+		Headers:        cfg.Tracing.Headers,
+		Insecure:       cfg.Tracing.Insecure,
+		Compression:    cfg.Tracing.Compression,
+		Enabled:        cfg.Tracing.Enabled,
+		SampleRate:     cfg.Tracing.SampleRate,
+		LogEndpoint:    cfg.Tracing.OTLPLogEndpoint,
+	}
+
+	// Initialize logger. When OTLPLogEndpoint is set, records Logger/
+	// ErrorLogger emit also reach the OTLP log backend, not just stdout;
+	// "HTTP Request" and "gRPC Request" records are sampled there at the
+	// tracing sample rate.
+	log, stopLogging := logger.NewWithOTLP(cfg.Environment, tracingCfg)
+	defer stopLogging()
 	defer log.Sync()
 
-	// Initialize Dapr client
-	daprClient, err := dapr.NewClient()
+	// Initialize distributed tracing before anything that might start a span.
+	// The sampler is a DynamicSampler rather than a value baked into the
+	// TracerProvider so the dynconfig.Loader built below can retune it
+	// without a restart.
+	sampler := tracing.NewDynamicSampler(cfg.Tracing.SampleRate)
+	tracingCfg.Sampler = sampler
+	tracerProvider, err := tracing.InitTracing(tracingCfg, log)
+	if err != nil {
+		log.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
+	defer tracing.Shutdown(context.Background(), tracerProvider, log)
+
+	// Initialize Dapr client, wrapped so service invocation and state store
+	// calls show up as client spans under whatever server span is active.
+	rawDaprClient, err := dapr.NewClient()
 	if err != nil {
 		log.Fatal("Failed to create Dapr client", zap.Error(err))
 	}
-	defer daprClient.Close()
+	defer rawDaprClient.Close()
+	daprClient := tracing.NewTracedClient(rawDaprClient, cfg.Name)
 
 	log.Info("Successfully connected to Dapr")
 
-	// Initialize Dapr Secret Manager
-	secretManager := secrets.NewDaprSecretManager(daprClient, "local-secret-store", log)
-	log.Info("Dapr Secret Manager initialized")
+	// Initialize the secret provider for the configured backend, then wrap
+	// it in a Rotator so a rotated JWT secret takes effect without a
+	// restart (see middleware.AuthMiddleware).
+	secretProvider, err := newSecretProvider(cfg.Secrets, daprClient, log)
+	if err != nil {
+		log.Fatal("Failed to initialize secret provider", zap.Error(err))
+	}
+
+	jwtRotator := secrets.NewRotator(secretProvider, secrets.RotatorConfig{
+		Key:             "JWT_SECRET",
+		RefreshInterval: cfg.Secrets.RefreshInterval,
+		RotationWindow:  cfg.Secrets.RotationWindow,
+	}, log)
+	if err := jwtRotator.Load(context.Background()); err != nil {
+		log.Fatal("Failed to load JWT secret", zap.Error(err))
+	}
+
+	rotatorCtx, stopRotator := context.WithCancel(context.Background())
+	defer stopRotator()
+	go jwtRotator.Run(rotatorCtx)
+
+	log.Info("Secret provider initialized", zap.String("backend", cfg.Secrets.Backend))
+
+	// Build the JWT verifier for the configured algorithm: HS256 validates
+	// against the Rotator above, JWKS validates RS256/ES256 tokens against
+	// keys discovered from an OIDC issuer.
+	var verifier middleware.TokenVerifier
+	if cfg.JWT.Algorithm == "JWKS" {
+		keySet := jwks.NewKeySet(cfg.JWT.OIDCIssuerURL, cfg.JWT.JWKSRefreshInterval, log)
+		if err := keySet.Load(context.Background()); err != nil {
+			log.Fatal("Failed to load JWKS", zap.Error(err))
+		}
+
+		jwksCtx, stopJWKS := context.WithCancel(context.Background())
+		defer stopJWKS()
+		go keySet.Run(jwksCtx)
+
+		verifier = &middleware.JWKSVerifier{KeySet: keySet}
+		log.Info("JWT verification using JWKS", zap.String("issuer", cfg.JWT.OIDCIssuerURL))
+	} else {
+		verifier = &middleware.HMACVerifier{Rotator: jwtRotator}
+	}
+
+	// Redis client backing the Idempotency-Key middleware and the webhook subsystem
+	redisClient, err := pkgredis.NewClient(pkgredis.RedisConfig{
+		Address:  cfg.Redis.Address,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+		PoolSize: cfg.Redis.PoolSize,
+	})
+	if err != nil {
+		log.Fatal("Failed to connect to Redis", zap.Error(err))
+	}
+	defer redisClient.Close()
+
+	// Outbound webhook subsystem: subscriptions are stored in Redis, and
+	// deliveries run on a background dispatcher for the lifetime of the process.
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	defer stopWebhooks()
+	subscriptionStore := webhooks.NewRedisSubscriptionStore(redisClient, log)
+	deadLetterStore := webhooks.NewRedisDeadLetterStore(redisClient, log)
+	dispatcher := webhooks.NewDispatcher(webhookCtx, subscriptionStore, deadLetterStore, log)
 
 	// Initialize repository with Dapr
-	cartRepo := repository.NewDaprCartRepository(daprClient, cfg.Dapr.StateStoreName, log)
+	cartRepo := repository.NewDaprCartRepository(daprClient, cfg.Dapr.StateStoreName, cfg.Dapr.CartIndexShardCount, cfg.Tracing.SlowOperationThreshold, log)
+
+	// When StorageConfig.Backend is "postgres", carts are durably persisted
+	// to Postgres with cartRepo's Redis state store demoted to a cache in
+	// front of it, so a Redis restart/eviction doesn't lose a cart. The
+	// default "redis" backend leaves cartRepo as the source of truth,
+	// matching prior behavior.
+	var appCartRepo repository.CartRepository = cartRepo
+	if cfg.Storage.Backend == "postgres" {
+		pgStore, err := postgres.NewStore(context.Background(), cfg.Storage.PostgresDSN, log)
+		if err != nil {
+			log.Fatal("Failed to connect to postgres durable store", zap.Error(err))
+		}
+		defer pgStore.Close()
+
+		cachedStore := storage.NewCachedStore(pgStore, storage.NewRedisStore(cartRepo), log)
+		appCartRepo = repository.NewDurableCartRepository(cartRepo, cachedStore, log)
+
+		reaper := storage.NewReaper(cachedStore, cfg.Storage.ReaperInterval, cfg.Storage.ReaperBatchSize, log, dispatcher)
+		reaperCtx, stopReaper := context.WithCancel(context.Background())
+		defer stopReaper()
+		go reaper.Run(reaperCtx)
+	}
+
+	// Recover sagas an earlier process instance never finished (e.g. a crash
+	// between reserving stock and persisting the cart) before serving
+	// traffic, so their reservations don't leak.
+	inventoryClient := clients.NewInventoryClient(daprClient, log)
+	sagaRecovery := saga.NewRecovery(daprClient, cfg.Dapr.StateStoreName, log)
+	sagaRecovery.Register("reserve_stock", services.ReserveStockCompensation(inventoryClient))
+	// read_cart and persist_cart have nothing to undo; registering them lets
+	// Recover drop a saga from the pending index once reserve_stock is
+	// compensated.
+	for _, step := range []string{"read_cart", "persist_cart"} {
+		sagaRecovery.Register(step, func(context.Context, json.RawMessage) error { return nil })
+	}
+	if err := sagaRecovery.Recover(context.Background()); err != nil {
+		log.Error("Failed to recover incomplete sagas", zap.Error(err))
+	}
 
 	// Initialize services with Dapr client for service invocation
-	cartService := services.NewCartService(cartRepo, daprClient, cfg, log)
+	// No dedicated OTel metrics SDK/exporter is wired up yet (see
+	// pkg/tracing.InitTracing for the span-only equivalent), so this reads
+	// through the global MeterProvider, which is a no-op until one is set;
+	// the instruments still get created so CartMetrics starts recording the
+	// moment a metrics pipeline is initialized here.
+	cartService := services.NewCartService(appCartRepo, daprClient, cfg, log, dispatcher, otel.GetMeterProvider())
 
 	// Initialize handlers
 	cartHandler := handlers.NewCartHandler(cartService, log)
+	webhookHandler := handlers.NewWebhookHandler(subscriptionStore, log)
+
+	// gRPC transport for the same CartService, so internal callers can avoid
+	// HTTP overhead. WatchCart's keyspace-notification fallback needs the
+	// Redis client already built above for the webhook subsystem.
+	grpcserver.SetRedisClient(redisClient)
+	cartServer := grpcserver.NewCartServer(cartService, log)
+	grpcSrv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			grpcserver.UnaryRecoveryInterceptor(log),
+			grpcserver.UnaryCorrelationInterceptor(),
+			grpcserver.UnaryAuthInterceptor(verifier),
+			grpcserver.UnaryLoggingInterceptor(log),
+		),
+	)
+	grpcserver.RegisterServer(grpcSrv, cartServer)
+
+	grpcListener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC", zap.String("port", cfg.Server.GRPCPort), zap.Error(err))
+	}
+	go func() {
+		log.Info("Starting Cart gRPC Service", zap.String("port", cfg.Server.GRPCPort))
+		if err := grpcSrv.Serve(grpcListener); err != nil {
+			log.Fatal("gRPC server failed", zap.Error(err))
+		}
+	}()
+
+	// Background sweeper for expired carts the Dapr state store hasn't
+	// TTL-evicted itself; only one replica actually sweeps at a time,
+	// coordinated via the same Distributed Lock API pkg/lock uses for
+	// cart mutations.
+	purgeLocker := lock.NewCartLocker(daprClient, lock.Config(cfg.Lock), log)
+	sweeper := purge.NewSweeper(cartRepo, purgeLocker, cfg.Cart.CleanupInterval, cfg.Cart.PurgeBatchSize, log)
+	adminHandler := handlers.NewAdminHandler(sweeper, log)
+
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	go sweeper.Run(sweeperCtx)
+
+	// Background dispatcher for the cart domain events CartService writes to
+	// the transactional outbox (see internal/events): polls for unpublished
+	// records and publishes them to the configured Dapr pub/sub component.
+	outboxDispatcher := events.NewOutboxDispatcher(
+		cartRepo, daprClient, cfg.Events.ComponentName, cfg.Events.Topic,
+		cfg.Events.PollInterval, cfg.Events.BatchSize, log,
+	)
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	defer stopOutbox()
+	go outboxDispatcher.Run(outboxCtx)
+
+	// Hot-reloadable settings: command-line flags and the process environment
+	// take precedence over cfg.ConfigFilePaths, falling back to the values
+	// config.Load already resolved. cartService's cart limits and the
+	// tracing sampler subscribe so a Reload (via WatchFiles or the admin
+	// endpoint/SIGHUP below) applies without a restart.
+	configLoader, err := dynconfig.NewLoader(cfg.ConfigFilePaths, dynconfig.NewCommandLineProvider(os.Args[1:]), map[string]interface{}{
+		"CART_MAX_ITEMS":      cfg.Cart.MaxItems,
+		"CART_MAX_ITEM_QTY":   cfg.Cart.MaxItemQty,
+		"CART_DEFAULT_TTL":    cfg.Cart.DefaultTTL,
+		"CART_GUEST_TTL":      cfg.Cart.GuestTTL,
+		"TRACING_SAMPLE_RATE": cfg.Tracing.SampleRate,
+	}, log)
+	if err != nil {
+		log.Fatal("Failed to initialize dynamic config loader", zap.Error(err))
+	}
+	cartService.Limits().Bind(configLoader)
+	configLoader.Watch("TRACING_SAMPLE_RATE", func(v dynconfig.Value) {
+		sampler.Update(v.Float(cfg.Tracing.SampleRate))
+	})
+
+	configWatchCtx, stopConfigWatch := context.WithCancel(context.Background())
+	defer stopConfigWatch()
+	go func() {
+		if err := configLoader.WatchFiles(configWatchCtx); err != nil {
+			log.Error("Config file watcher stopped", zap.Error(err))
+		}
+	}()
 
 	// Setup Gin router
 	if cfg.Environment == "production" {
@@ -84,6 +315,7 @@ func main() {
 	// Middleware
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(middleware.Tracing(cfg.Name))
 	router.Use(middleware.CorrelationID())
 	router.Use(middleware.Logger(log))
 
@@ -148,36 +380,89 @@ func main() {
 
 	// Metrics endpoint
 	router.GET("/metrics", func(c *gin.Context) {
+		sweeperMetrics := sweeper.Metrics()
+		productCacheMetrics := cartService.ProductCacheMetrics()
 		c.JSON(http.StatusOK, gin.H{
 			"service":   cfg.Name,
 			"timestamp": time.Now().UTC(),
 			"version":   cfg.Version,
+			"purge_sweeper": gin.H{
+				"total_scanned": sweeperMetrics.TotalScanned,
+				"total_deleted": sweeperMetrics.TotalDeleted,
+				"last_run_at":   sweeperMetrics.LastRunAt,
+			},
+			"product_cache": gin.H{
+				"hits":      productCacheMetrics.Hits,
+				"misses":    productCacheMetrics.Misses,
+				"coalesced": productCacheMetrics.Coalesced,
+			},
 		})
 	})
 
 	// API routes
 	v1 := router.Group("/api/v1")
 	{
-		// Cart routes with authentication middleware (using Dapr secrets)
+		// Cart routes with authentication and scope-based authorization
 		cartRoutes := v1.Group("/cart")
-		cartRoutes.Use(middleware.AuthMiddleware(secretManager, log))
+		cartRoutes.Use(middleware.AuthMiddleware(verifier, log))
+		cartRoutes.Use(middleware.CartContext())
+		idempotency := middleware.Idempotency(redisClient, cfg.Cart.IdempotencyTTL, log)
+		readScope := middleware.RequireScope("cart:read")
+		writeScope := middleware.RequireScope("cart:write")
 		{
-			cartRoutes.GET("", cartHandler.GetCart)
-			cartRoutes.POST("/items", cartHandler.AddItem)
-			cartRoutes.PUT("/items/:productId", cartHandler.UpdateItem)
-			cartRoutes.DELETE("/items/:productId", cartHandler.RemoveItem)
-			cartRoutes.DELETE("", cartHandler.ClearCart)
-			cartRoutes.POST("/transfer", cartHandler.TransferCart)
+			cartRoutes.GET("", readScope, cartHandler.GetCart)
+			cartRoutes.POST("/items", writeScope, idempotency, cartHandler.AddItem)
+			cartRoutes.PUT("/items/:productId", writeScope, idempotency, cartHandler.UpdateItem)
+			cartRoutes.DELETE("/items/:productId", writeScope, idempotency, cartHandler.RemoveItem)
+			cartRoutes.DELETE("", writeScope, idempotency, cartHandler.ClearCart)
+			cartRoutes.POST("/transfer", writeScope, idempotency, cartHandler.TransferCart)
+			cartRoutes.POST("/items:bulk", writeScope, idempotency, cartHandler.BulkApply)
 		}
 
 		// Guest cart routes (no authentication required)
 		guestRoutes := v1.Group("/guest/cart")
 		{
 			guestRoutes.GET("/:guestId", cartHandler.GetGuestCart)
-			guestRoutes.POST("/:guestId/items", cartHandler.AddGuestItem)
-			guestRoutes.PUT("/:guestId/items/:productId", cartHandler.UpdateGuestItem)
-			guestRoutes.DELETE("/:guestId/items/:productId", cartHandler.RemoveGuestItem)
-			guestRoutes.DELETE("/:guestId", cartHandler.ClearGuestCart)
+			guestRoutes.POST("/:guestId/items", idempotency, cartHandler.AddGuestItem)
+			guestRoutes.PUT("/:guestId/items/:productId", idempotency, cartHandler.UpdateGuestItem)
+			guestRoutes.DELETE("/:guestId/items/:productId", idempotency, cartHandler.RemoveGuestItem)
+			guestRoutes.DELETE("/:guestId", idempotency, cartHandler.ClearGuestCart)
+			guestRoutes.POST("/:guestId/items:bulk", idempotency, cartHandler.BulkApplyGuest)
+		}
+
+		// Webhook subscription management (authenticated, admin-scoped)
+		webhookRoutes := v1.Group("/webhooks/subscriptions")
+		webhookRoutes.Use(middleware.AuthMiddleware(verifier, log))
+		webhookRoutes.Use(middleware.RequireScope("cart:admin"))
+		{
+			webhookRoutes.POST("", webhookHandler.CreateSubscription)
+			webhookRoutes.GET("", webhookHandler.ListSubscriptions)
+			webhookRoutes.PUT("/:subscriptionId", webhookHandler.UpdateSubscription)
+			webhookRoutes.DELETE("/:subscriptionId", webhookHandler.DeleteSubscription)
+		}
+
+		// Admin operational endpoints (authenticated, admin-scoped)
+		adminRoutes := v1.Group("/admin/carts")
+		adminRoutes.Use(middleware.AuthMiddleware(verifier, log))
+		adminRoutes.Use(middleware.RequireScope("cart:admin"))
+		{
+			adminRoutes.POST("/purge", adminHandler.PurgeCarts)
+		}
+
+		// Config reload (authenticated, admin-scoped): lets an operator push
+		// a config file change through immediately instead of waiting on
+		// WatchFiles, without needing shell access to send SIGHUP.
+		configRoutes := v1.Group("/admin/config")
+		configRoutes.Use(middleware.AuthMiddleware(verifier, log))
+		configRoutes.Use(middleware.RequireScope("cart:admin"))
+		{
+			configRoutes.POST("/reload", func(c *gin.Context) {
+				if err := configLoader.Reload(); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+					return
+				}
+				c.JSON(http.StatusOK, gin.H{"success": true, "message": "configuration reloaded"})
+			})
 		}
 	}
 
@@ -192,21 +477,38 @@ func main() {
 
 	// Graceful shutdown
 	go func() {
-		log.Info("Starting Cart Service", 
+		log.Info("Starting Cart Service",
 			zap.String("name", cfg.Name),
 			zap.String("version", cfg.Version),
 			zap.String("port", cfg.Server.Port),
 			zap.String("environment", cfg.Environment))
-		
+
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
 
-	// Wait for interrupt signal to gracefully shutdown the server
+	// Wait for interrupt signal to gracefully shutdown the server. SIGHUP is
+	// handled separately as a config reload, the conventional Unix signal
+	// for "re-read your config" (e.g. nginx, sshd), and doesn't terminate
+	// the loop.
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	for {
+		select {
+		case <-reload:
+			log.Info("Received SIGHUP, reloading configuration")
+			if err := configLoader.Reload(); err != nil {
+				log.Error("Failed to reload configuration", zap.Error(err))
+			}
+			continue
+		case <-quit:
+		}
+		break
+	}
 
 	log.Info("Shutting down Cart Service...")
 
@@ -216,6 +518,33 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Fatal("Server forced to shutdown", zap.Error(err))
 	}
+	grpcSrv.GracefulStop()
 
 	log.Info("Cart Service stopped")
 }
+
+// newSecretProvider builds the secrets.SecretProvider selected by
+// cfg.Backend ("dapr", "vault", "aws", or "env").
+func newSecretProvider(cfg config.SecretsConfig, daprClient dapr.Client, log *zap.Logger) (secrets.SecretProvider, error) {
+	switch cfg.Backend {
+	case "vault":
+		return secrets.NewVaultSecretProvider(secrets.VaultConfig{
+			Address:    cfg.VaultAddress,
+			Token:      cfg.VaultToken,
+			RoleID:     cfg.VaultRoleID,
+			SecretID:   cfg.VaultSecretID,
+			SecretPath: cfg.VaultSecretPath,
+		}, log)
+	case "aws":
+		return secrets.NewAWSSecretProvider(context.Background(), secrets.AWSConfig{
+			Region:   cfg.AWSRegion,
+			SecretID: cfg.AWSSecretID,
+		}, log)
+	case "env":
+		return secrets.NewEnvSecretProvider(), nil
+	case "dapr":
+		return secrets.NewDaprSecretProvider(daprClient, cfg.DaprStoreName, log), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets backend '%s'", cfg.Backend)
+	}
+}
@@ -0,0 +1,90 @@
+// Package cartctx carries cart-scoped request context (user, session,
+// currency, pricing experiment) across service boundaries using OpenTelemetry
+// Baggage, so it survives an HTTP or gRPC hop without an explicit parameter
+// at every call site.
+package cartctx
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Baggage member keys. These are also used as span attribute keys when
+// middleware stamps the active span.
+const (
+	KeyUserID     = "cart.user_id"
+	KeySessionID  = "cart.session_id"
+	KeyCurrency   = "cart.currency"
+	KeyExperiment = "pricing.experiment"
+)
+
+// CartContext is the set of request-scoped values propagated as baggage.
+type CartContext struct {
+	UserID     string
+	SessionID  string
+	Currency   string
+	Experiment string
+}
+
+// WithBaggage attaches cc's non-empty fields to ctx as OTEL baggage members,
+// returning a new context. Existing baggage members not covered by cc are
+// left untouched.
+func WithBaggage(ctx context.Context, cc CartContext) (context.Context, error) {
+	bag := baggage.FromContext(ctx)
+
+	for key, value := range map[string]string{
+		KeyUserID:     cc.UserID,
+		KeySessionID:  cc.SessionID,
+		KeyCurrency:   cc.Currency,
+		KeyExperiment: cc.Experiment,
+	} {
+		if value == "" {
+			continue
+		}
+		member, err := baggage.NewMember(key, value)
+		if err != nil {
+			return ctx, err
+		}
+		bag, err = bag.SetMember(member)
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// FromContext reads the cart baggage members back out of ctx. Missing
+// members come back as empty strings.
+func FromContext(ctx context.Context) CartContext {
+	bag := baggage.FromContext(ctx)
+	return CartContext{
+		UserID:     bag.Member(KeyUserID).Value(),
+		SessionID:  bag.Member(KeySessionID).Value(),
+		Currency:   bag.Member(KeyCurrency).Value(),
+		Experiment: bag.Member(KeyExperiment).Value(),
+	}
+}
+
+// StampSpan copies ctx's cart baggage members onto the span active in ctx,
+// so they show up alongside the span's own attributes in the trace backend.
+func StampSpan(ctx context.Context) {
+	cc := FromContext(ctx)
+	span := trace.SpanFromContext(ctx)
+
+	if cc.UserID != "" {
+		span.SetAttributes(attribute.String(KeyUserID, cc.UserID))
+	}
+	if cc.SessionID != "" {
+		span.SetAttributes(attribute.String(KeySessionID, cc.SessionID))
+	}
+	if cc.Currency != "" {
+		span.SetAttributes(attribute.String(KeyCurrency, cc.Currency))
+	}
+	if cc.Experiment != "" {
+		span.SetAttributes(attribute.String(KeyExperiment, cc.Experiment))
+	}
+}
@@ -12,20 +12,35 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Name        string
-	Version     string
-	Environment string
-	Server      ServerConfig
-	Dapr        DaprConfig
-	JWT         JWTConfig
-	CORS        CORSConfig
-	Cart        CartConfig
-	Services    ServicesConfig
-	Tracing     TracingConfig
+	Name         string
+	Version      string
+	Environment  string
+	Server       ServerConfig
+	Dapr         DaprConfig
+	JWT          JWTConfig
+	CORS         CORSConfig
+	Cart         CartConfig
+	Services     ServicesConfig
+	ProductCache ProductCacheConfig
+	CartCache    CartSessionCacheConfig
+	Tracing      TracingConfig
+	Storage      StorageConfig
+	Redis        RedisConfig
+	Lock         LockConfig
+	Secrets      SecretsConfig
+	Events       EventsConfig
+
+	// ConfigFilePaths lists YAML/JSON files dynconfig.NewLoader watches for
+	// hot-reloadable settings (see config.LiveCartLimits), in addition to the
+	// environment variables Load itself reads once at startup. Empty by
+	// default, since most deployments only need the SIGHUP/admin-triggered
+	// Reload path, not a mounted file.
+	ConfigFilePaths []string
 }
 
 type ServerConfig struct {
 	Port         string
+	GRPCPort     string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 }
@@ -36,10 +51,21 @@ type DaprConfig struct {
 	StateStoreName string
 	AppID          string
 	AppPort        string
+	// CartIndexShardCount is the number of shards DaprCartRepository splits
+	// its secondary cart-key index across (see
+	// DaprCartRepository.ListAllCartKeys). More shards shrink each shard's
+	// CAS-retry contention window at the cost of more state-store keys.
+	CartIndexShardCount int
 }
 
+// JWTConfig selects how inbound JWTs are verified. Algorithm is one of
+// "HS256" (default: shared secret from Secrets/Rotator) or "JWKS" (RS256/
+// ES256 verified against OIDCIssuerURL's published key set).
 type JWTConfig struct {
-	SecretKey string
+	SecretKey           string
+	Algorithm           string
+	OIDCIssuerURL       string
+	JWKSRefreshInterval time.Duration
 }
 
 type CORSConfig struct {
@@ -47,11 +73,65 @@ type CORSConfig struct {
 }
 
 type CartConfig struct {
-	DefaultTTL     time.Duration
-	GuestTTL       time.Duration
-	MaxItems       int
-	MaxItemQty     int
+	DefaultTTL time.Duration
+	GuestTTL   time.Duration
+	MaxItems   int
+	MaxItemQty int
+	// CleanupInterval is how often the background purge sweeper (see
+	// internal/purge) scans the state store for expired carts.
 	CleanupInterval time.Duration
+	// PurgeBatchSize bounds how many expired carts the purge sweeper
+	// requests per page from the repository.
+	PurgeBatchSize int
+	// ExperimentMaxItemQty overrides MaxItemQty for the pricing.experiment
+	// baggage value propagated by internal/cartctx, e.g. {"holiday-2025": 20}.
+	ExperimentMaxItemQty map[string]int
+	IdempotencyTTL       time.Duration
+}
+
+// RedisConfig configures the raw go-redis client used by middleware.Idempotency,
+// the webhook subscription and dead-letter stores, and gRPC WatchCart.
+type RedisConfig struct {
+	Address  string
+	Password string
+	DB       int
+	PoolSize int
+}
+
+// LockConfig controls the distributed lock that pkg/lock acquires around
+// cart mutations via Dapr's Distributed Lock API.
+type LockConfig struct {
+	StoreName  string
+	LeaseTTL   time.Duration
+	MaxRetries int
+	RetryDelay time.Duration
+}
+
+// SecretsConfig selects the backing secret store used by pkg/secrets and
+// controls background rotation of the JWT signing secret. Backend is one
+// of "dapr", "vault", "aws", or "env".
+type SecretsConfig struct {
+	Backend         string
+	DaprStoreName   string
+	VaultAddress    string
+	VaultToken      string
+	VaultRoleID     string
+	VaultSecretID   string
+	VaultSecretPath string
+	AWSRegion       string
+	AWSSecretID     string
+	RefreshInterval time.Duration
+	RotationWindow  time.Duration
+}
+
+// EventsConfig controls the Dapr pub/sub component and topic
+// internal/events.OutboxDispatcher publishes cart domain events to, and how
+// often it polls CartRepository for unpublished ones.
+type EventsConfig struct {
+	ComponentName string
+	Topic         string
+	PollInterval  time.Duration
+	BatchSize     int
 }
 
 type ServicesConfig struct {
@@ -66,12 +146,62 @@ type ServicesConfig struct {
 	UserServiceURL      string
 }
 
+// ProductCacheConfig controls clients.CachedProductClient, the in-process
+// cache/batcher CartService wraps clients.ProductClient with.
+type ProductCacheConfig struct {
+	// TTL is how long a looked-up product stays cached before it's treated
+	// as a miss again, so cart totals eventually reflect a price change.
+	TTL time.Duration
+	// MaxSize bounds the cache's entry count; once full, the
+	// least-recently-used product is evicted to make room.
+	MaxSize int
+	// BatchWindow is how long CachedProductClient waits after the first
+	// cache-miss GetProduct call before issuing one GetProducts batch call
+	// covering every productID requested in that window.
+	BatchWindow time.Duration
+}
+
+// CartSessionCacheConfig controls repository.CachedCartRepository, the
+// in-process read-through cache CartService wraps repository.CartRepository
+// with so a single request's GetCart/ValidateCart/GetCartSummary sequence
+// doesn't round-trip to Redis more than once.
+type CartSessionCacheConfig struct {
+	// TTL is how long a looked-up cart stays cached before it's treated as
+	// a miss again. A non-positive TTL disables the cache. Kept short
+	// (well under a request's lifetime) since every write invalidates its
+	// entry immediately anyway — TTL only bounds how stale a read can be if
+	// the cart changed through a path CachedCartRepository doesn't see.
+	TTL time.Duration
+}
+
+// StorageConfig selects the durable persistence backend behind the Redis
+// cache. Backend is one of "redis" (cache only, current default behavior)
+// or "postgres" (write-through CachedStore with Redis as the cache tier).
+type StorageConfig struct {
+	Backend         string
+	PostgresDSN     string
+	ReaperInterval  time.Duration
+	ReaperBatchSize int
+}
+
 type TracingConfig struct {
 	Enabled        bool
 	ServiceName    string
 	ServiceVersion string
-	JaegerEndpoint string
+	ExporterType   string
+	Endpoint       string
+	Headers        map[string]string
+	Insecure       bool
+	Compression    string
 	SampleRate     float64
+	// OTLPLogEndpoint is the OTLP gRPC endpoint logger.NewWithOTLP exports
+	// log records to; left empty, logging stays stdout-only.
+	OTLPLogEndpoint string
+	// SlowOperationThreshold is the threshold DaprCartRepository's
+	// tracing.StepTrace calls use to decide whether a cart operation's
+	// per-step timing breakdown is worth logging (see
+	// tracing.StepTrace.LogIfLong).
+	SlowOperationThreshold time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -82,33 +212,42 @@ func Load() *Config {
 	}
 
 	return &Config{
-		Name:        getEnv("NAME", "cart-service"),
-		Version:     getEnv("VERSION", "1.0.0"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Name:            getEnv("NAME", "cart-service"),
+		Version:         getEnv("VERSION", "1.0.0"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		ConfigFilePaths: getSliceEnv("CART_CONFIG_FILES", []string{}),
 		Server: ServerConfig{
 			Port:         getEnv("PORT", "1008"),
+			GRPCPort:     getEnv("GRPC_PORT", "9008"),
 			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 30*time.Second),
 			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 30*time.Second),
 		},
 		Dapr: DaprConfig{
-			HTTPPort:       getEnv("DAPR_HTTP_PORT", "3508"),
-			GRPCPort:       getEnv("DAPR_GRPC_PORT", "50008"),
-			StateStoreName: getEnv("DAPR_STATE_STORE", "statestore"),
-			AppID:          getEnv("DAPR_APP_ID", "cart-service"),
-			AppPort:        getEnv("DAPR_APP_PORT", "1008"),
+			HTTPPort:            getEnv("DAPR_HTTP_PORT", "3508"),
+			GRPCPort:            getEnv("DAPR_GRPC_PORT", "50008"),
+			StateStoreName:      getEnv("DAPR_STATE_STORE", "statestore"),
+			AppID:               getEnv("DAPR_APP_ID", "cart-service"),
+			AppPort:             getEnv("DAPR_APP_PORT", "1008"),
+			CartIndexShardCount: getIntEnv("DAPR_CART_INDEX_SHARD_COUNT", 16),
 		},
 		JWT: JWTConfig{
-			SecretKey: getEnv("JWT_SECRET", "your-256-bit-secret"),
+			SecretKey:           getEnv("JWT_SECRET", "your-256-bit-secret"),
+			Algorithm:           getEnv("JWT_ALGORITHM", "HS256"),
+			OIDCIssuerURL:       getEnv("OIDC_ISSUER_URL", ""),
+			JWKSRefreshInterval: getDurationEnv("JWKS_REFRESH_INTERVAL", 10*time.Minute),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: getSliceEnv("CORS_ALLOWED_ORIGINS", []string{"*"}),
 		},
 		Cart: CartConfig{
-			DefaultTTL:      getDurationEnv("CART_DEFAULT_TTL", 30*24*time.Hour), // 30 days
-			GuestTTL:        getDurationEnv("CART_GUEST_TTL", 3*24*time.Hour),    // 3 days
-			MaxItems:        getIntEnv("CART_MAX_ITEMS", 100),
-			MaxItemQty:      getIntEnv("CART_MAX_ITEM_QTY", 10),
-			CleanupInterval: getDurationEnv("CART_CLEANUP_INTERVAL", 1*time.Hour),
+			DefaultTTL:           getDurationEnv("CART_DEFAULT_TTL", 30*24*time.Hour), // 30 days
+			GuestTTL:             getDurationEnv("CART_GUEST_TTL", 3*24*time.Hour),    // 3 days
+			MaxItems:             getIntEnv("CART_MAX_ITEMS", 100),
+			MaxItemQty:           getIntEnv("CART_MAX_ITEM_QTY", 10),
+			CleanupInterval:      getDurationEnv("CART_CLEANUP_INTERVAL", 1*time.Hour),
+			PurgeBatchSize:       getIntEnv("CART_PURGE_BATCH_SIZE", 100),
+			ExperimentMaxItemQty: parseIntMapEnv("CART_EXPERIMENT_MAX_ITEM_QTY"),
+			IdempotencyTTL:       getDurationEnv("CART_IDEMPOTENCY_TTL", 24*time.Hour),
 		},
 		Services: ServicesConfig{
 			ProductServiceURL:   getEnv("PRODUCT_SERVICE_URL", "http://localhost:8081"),
@@ -116,12 +255,67 @@ func Load() *Config {
 			OrderServiceURL:     getEnv("ORDER_SERVICE_URL", "http://localhost:8083"),
 			UserServiceURL:      getEnv("USER_SERVICE_URL", "http://localhost:8084"),
 		},
+		ProductCache: ProductCacheConfig{
+			TTL:         getDurationEnv("PRODUCT_CACHE_TTL", 30*time.Second),
+			MaxSize:     getIntEnv("PRODUCT_CACHE_MAX_SIZE", 10000),
+			BatchWindow: getDurationEnv("PRODUCT_CACHE_BATCH_WINDOW", 5*time.Millisecond),
+		},
+		CartCache: CartSessionCacheConfig{
+			TTL: getDurationEnv("CART_SESSION_CACHE_TTL", 2*time.Second),
+		},
 		Tracing: TracingConfig{
 			Enabled:        getBoolEnv("TRACING_ENABLED", true),
 			ServiceName:    getEnv("TRACING_SERVICE_NAME", "cart-service"),
 			ServiceVersion: getEnv("TRACING_SERVICE_VERSION", "1.0.0"),
-			JaegerEndpoint: getEnv("TRACING_JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
-			SampleRate:     getFloatEnv("TRACING_SAMPLE_RATE", 1.0),
+			// ExporterType is one of "otlp-grpc", "otlp-http", "jaeger", "stdout",
+			// "none". Endpoint/Headers may be left empty to fall back to the
+			// standard OTEL_EXPORTER_OTLP_*/OTEL_EXPORTER_JAEGER_ENDPOINT
+			// environment variables.
+			ExporterType:           getEnv("TRACING_EXPORTER_TYPE", "otlp-grpc"),
+			Endpoint:               getEnv("TRACING_OTLP_ENDPOINT", ""),
+			Headers:                parseHeadersEnv("TRACING_OTLP_HEADERS"),
+			Insecure:               getBoolEnv("TRACING_OTLP_INSECURE", true),
+			Compression:            getEnv("TRACING_OTLP_COMPRESSION", "gzip"),
+			SampleRate:             getFloatEnv("TRACING_SAMPLE_RATE", 1.0),
+			OTLPLogEndpoint:        getEnv("TRACING_OTLP_LOG_ENDPOINT", ""),
+			SlowOperationThreshold: getDurationEnv("TRACING_SLOW_OPERATION_THRESHOLD", 200*time.Millisecond),
+		},
+		Storage: StorageConfig{
+			Backend:         getEnv("STORAGE_BACKEND", "redis"),
+			PostgresDSN:     getEnv("STORAGE_POSTGRES_DSN", ""),
+			ReaperInterval:  getDurationEnv("STORAGE_REAPER_INTERVAL", 5*time.Minute),
+			ReaperBatchSize: getIntEnv("STORAGE_REAPER_BATCH_SIZE", 100),
+		},
+		Redis: RedisConfig{
+			Address:  getEnv("REDIS_ADDRESS", "localhost:6379"),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       getIntEnv("REDIS_DB", 0),
+			PoolSize: getIntEnv("REDIS_POOL_SIZE", 10),
+		},
+		Lock: LockConfig{
+			StoreName:  getEnv("DAPR_LOCK_STORE", "lockstore"),
+			LeaseTTL:   getDurationEnv("CART_LOCK_LEASE_TTL", 30*time.Second),
+			MaxRetries: getIntEnv("CART_LOCK_MAX_RETRIES", 3),
+			RetryDelay: getDurationEnv("CART_LOCK_RETRY_DELAY", 100*time.Millisecond),
+		},
+		Events: EventsConfig{
+			ComponentName: getEnv("EVENTS_PUBSUB_COMPONENT", "cart-pubsub"),
+			Topic:         getEnv("EVENTS_TOPIC", "cart-events"),
+			PollInterval:  getDurationEnv("EVENTS_POLL_INTERVAL", 5*time.Second),
+			BatchSize:     getIntEnv("EVENTS_BATCH_SIZE", 50),
+		},
+		Secrets: SecretsConfig{
+			Backend:         getEnv("SECRETS_BACKEND", "dapr"),
+			DaprStoreName:   getEnv("DAPR_SECRET_STORE", "local-secret-store"),
+			VaultAddress:    getEnv("VAULT_ADDR", ""),
+			VaultToken:      getEnv("VAULT_TOKEN", ""),
+			VaultRoleID:     getEnv("VAULT_ROLE_ID", ""),
+			VaultSecretID:   getEnv("VAULT_SECRET_ID", ""),
+			VaultSecretPath: getEnv("VAULT_SECRET_PATH", "secret/data/cart-service"),
+			AWSRegion:       getEnv("AWS_REGION", "us-east-1"),
+			AWSSecretID:     getEnv("AWS_SECRET_ID", "cart-service/jwt"),
+			RefreshInterval: getDurationEnv("SECRETS_REFRESH_INTERVAL", 5*time.Minute),
+			RotationWindow:  getDurationEnv("SECRETS_ROTATION_WINDOW", 10*time.Minute),
 		},
 	}
 }
@@ -168,6 +362,51 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// parseHeadersEnv parses a comma-separated list of key=value pairs (e.g.
+// "api-key=secret,x-tenant=acme") into a header map for the OTLP exporters.
+func parseHeadersEnv(key string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	return headers
+}
+
+// parseIntMapEnv parses a comma-separated list of key=value pairs (e.g.
+// "holiday-2025=20,flash-sale=5") into a string->int map, skipping any pair
+// whose value isn't a valid integer.
+func parseIntMapEnv(key string) map[string]int {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	result := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		intValue, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil {
+			continue
+		}
+		result[strings.TrimSpace(kv[0])] = intValue
+	}
+
+	return result
+}
+
 func getFloatEnv(key string, defaultValue float64) float64 {
 	if value := os.Getenv(key); value != "" {
 		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
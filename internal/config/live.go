@@ -0,0 +1,67 @@
+package config
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/aioutlet/cart-service/pkg/dynconfig"
+)
+
+// LiveCartLimits is an atomically-swappable snapshot of the CartConfig
+// fields operators tune most often in response to load or a promotion
+// (MaxItems, MaxItemQty, DefaultTTL, GuestTTL). CartService reads through
+// it instead of a fixed CartConfig so Bind's dynconfig.Provider watchers can
+// change these without a restart.
+type LiveCartLimits struct {
+	snapshot atomic.Value // cartLimitsSnapshot
+}
+
+type cartLimitsSnapshot struct {
+	MaxItems   int
+	MaxItemQty int
+	DefaultTTL time.Duration
+	GuestTTL   time.Duration
+}
+
+// NewLiveCartLimits creates a LiveCartLimits seeded with initial's values.
+func NewLiveCartLimits(initial CartConfig) *LiveCartLimits {
+	l := &LiveCartLimits{}
+	l.snapshot.Store(cartLimitsSnapshot{
+		MaxItems:   initial.MaxItems,
+		MaxItemQty: initial.MaxItemQty,
+		DefaultTTL: initial.DefaultTTL,
+		GuestTTL:   initial.GuestTTL,
+	})
+	return l
+}
+
+// Snapshot returns the currently active limits.
+func (l *LiveCartLimits) Snapshot() (maxItems, maxItemQty int, defaultTTL, guestTTL time.Duration) {
+	s := l.snapshot.Load().(cartLimitsSnapshot)
+	return s.MaxItems, s.MaxItemQty, s.DefaultTTL, s.GuestTTL
+}
+
+// Bind registers Watch callbacks on provider for each of the four limits,
+// keyed by the same environment variable names config.Load reads them from
+// (e.g. "CART_MAX_ITEMS"), so a file change or Reload picked up by provider
+// atomically updates what Snapshot returns.
+func (l *LiveCartLimits) Bind(provider dynconfig.Provider) {
+	provider.Watch("CART_MAX_ITEMS", func(v dynconfig.Value) {
+		l.update(func(s *cartLimitsSnapshot) { s.MaxItems = v.Int(s.MaxItems) })
+	})
+	provider.Watch("CART_MAX_ITEM_QTY", func(v dynconfig.Value) {
+		l.update(func(s *cartLimitsSnapshot) { s.MaxItemQty = v.Int(s.MaxItemQty) })
+	})
+	provider.Watch("CART_DEFAULT_TTL", func(v dynconfig.Value) {
+		l.update(func(s *cartLimitsSnapshot) { s.DefaultTTL = v.Duration(s.DefaultTTL) })
+	})
+	provider.Watch("CART_GUEST_TTL", func(v dynconfig.Value) {
+		l.update(func(s *cartLimitsSnapshot) { s.GuestTTL = v.Duration(s.GuestTTL) })
+	})
+}
+
+func (l *LiveCartLimits) update(apply func(*cartLimitsSnapshot)) {
+	s := l.snapshot.Load().(cartLimitsSnapshot)
+	apply(&s)
+	l.snapshot.Store(s)
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/repository"
+	dapr "github.com/dapr/go-sdk/client"
+	"go.uber.org/zap"
+)
+
+// Publisher is the subset of dapr.Client OutboxDispatcher needs, so tests
+// can substitute a fake that simulates a publish failure.
+type Publisher interface {
+	PublishEvent(ctx context.Context, pubsubName, topicName string, data interface{}, opts ...dapr.PublishEventOption) error
+}
+
+// defaultBatchSize bounds how many outbox records OutboxDispatcher requests
+// from the repository per poll, when NewOutboxDispatcher isn't given one.
+const defaultBatchSize = 50
+
+// OutboxDispatcher polls CartRepository for unpublished models.OutboxRecord
+// entries on its own interval and publishes each to the configured Dapr
+// pub/sub component/topic, marking it done only once the publish succeeds.
+// A publish failure (or a crash mid-dispatch) just leaves the record
+// unpublished for the next poll to retry, rather than losing the event.
+type OutboxDispatcher struct {
+	repo          repository.CartRepository
+	publisher     Publisher
+	componentName string
+	topic         string
+	interval      time.Duration
+	batchSize     int
+	logger        *zap.Logger
+}
+
+// NewOutboxDispatcher creates an OutboxDispatcher. Call Run (or Dispatch for
+// a single pass) to start polling.
+func NewOutboxDispatcher(repo repository.CartRepository, publisher Publisher, componentName, topic string, interval time.Duration, batchSize int, logger *zap.Logger) *OutboxDispatcher {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &OutboxDispatcher{
+		repo:          repo,
+		publisher:     publisher,
+		componentName: componentName,
+		topic:         topic,
+		interval:      interval,
+		batchSize:     batchSize,
+		logger:        logger,
+	}
+}
+
+// Run blocks, dispatching on OutboxDispatcher's interval until ctx is done.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Dispatch(ctx); err != nil {
+				d.logger.Error("Outbox dispatch failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Dispatch runs a single poll-and-publish pass immediately.
+func (d *OutboxDispatcher) Dispatch(ctx context.Context) error {
+	records, err := d.repo.ListPendingOutboxRecords(ctx, d.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list pending outbox records: %w", err)
+	}
+
+	for _, record := range records {
+		ce := toCloudEvent(record)
+		if err := d.publisher.PublishEvent(ctx, d.componentName, d.topic, ce, dapr.PublishEventWithContentType("application/cloudevents+json")); err != nil {
+			d.logger.Warn("Failed to publish outbox event, leaving for retry",
+				zap.String("recordID", record.ID),
+				zap.String("type", string(record.Type)),
+				zap.Error(err))
+			continue
+		}
+
+		if err := d.repo.MarkOutboxRecordPublished(ctx, record.ID); err != nil {
+			d.logger.Error("Failed to mark outbox record published after successful publish",
+				zap.String("recordID", record.ID),
+				zap.Error(err))
+			continue
+		}
+
+		d.logger.Debug("Outbox event published",
+			zap.String("recordID", record.ID), zap.String("type", string(record.Type)))
+	}
+
+	return nil
+}
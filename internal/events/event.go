@@ -0,0 +1,72 @@
+// Package events publishes cart domain lifecycle events as CloudEvents to a
+// Dapr pub/sub component. CartService records each event as a
+// models.OutboxRecord alongside the cart mutation that produced it, in the
+// same Dapr state transaction (see repository.CartRepository.SaveCartWithOutbox),
+// so a crash between saving the cart and publishing the event can't lose it.
+// OutboxDispatcher polls for unpublished records and publishes them, rather
+// than publishing inline from the request path.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/google/uuid"
+)
+
+// cloudEventSource identifies cart-service as the originator in each
+// published CloudEvent's "source" attribute.
+const cloudEventSource = "aioutlet/cart-service"
+
+// CloudEvent is the CNCF CloudEvents v1.0 envelope OutboxDispatcher
+// publishes to the configured Dapr pub/sub topic.
+type CloudEvent struct {
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	SpecVersion     string          `json:"specversion"`
+	Type            string          `json:"type"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// eventData is the CloudEvent "data" payload for every cart domain event:
+// the mutated cart plus who it happened to. Cart is nil when the mutation
+// doesn't have one to attach (e.g. a cleared cart is deleted, not saved).
+type eventData struct {
+	UserID string       `json:"userId"`
+	Cart   *models.Cart `json:"cart,omitempty"`
+}
+
+// NewRecord builds the models.OutboxRecord a CartService mutation writes via
+// CartRepository.SaveCartWithOutbox for eventType, ready for
+// OutboxDispatcher to publish later.
+func NewRecord(eventType models.OutboxEventType, userID string, cart *models.Cart) (models.OutboxRecord, error) {
+	data, err := json.Marshal(eventData{UserID: userID, Cart: cart})
+	if err != nil {
+		return models.OutboxRecord{}, fmt.Errorf("failed to marshal outbox event data: %w", err)
+	}
+
+	return models.OutboxRecord{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		UserID:    userID,
+		Payload:   data,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// toCloudEvent wraps record in a CloudEvents v1.0 envelope for publishing.
+func toCloudEvent(record models.OutboxRecord) CloudEvent {
+	return CloudEvent{
+		ID:              record.ID,
+		Source:          cloudEventSource,
+		SpecVersion:     "1.0",
+		Type:            string(record.Type),
+		Time:            record.CreatedAt,
+		DataContentType: "application/json",
+		Data:            record.Payload,
+	}
+}
@@ -0,0 +1,211 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/middleware"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// correlationIDKey is an unexported context key, mirroring how
+// middleware.CorrelationID stores the ID in the Gin context rather than
+// handing callers a raw string key.
+type correlationIDKey struct{}
+
+// userIDKey is an unexported context key holding the caller-supplied user ID
+// metadata, mirroring middleware.CartContext's userID context value for HTTP.
+type userIDKey struct{}
+
+const (
+	correlationIDMetadataKey = "x-correlation-id"
+	userIDMetadataKey        = "x-user-id"
+)
+
+// UnaryCorrelationInterceptor extracts the correlation ID and user ID from
+// inbound gRPC metadata (falling back to a generated UUID for the
+// correlation ID, same as middleware.CorrelationID does for HTTP), stores
+// them on the context, and adds the correlation ID as a span attribute so
+// gRPC and HTTP requests are correlated the same way in traces and logs.
+func UnaryCorrelationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		correlationID := metadataValue(ctx, correlationIDMetadataKey)
+		if correlationID == "" {
+			correlationID = uuid.New().String()
+		}
+
+		ctx = context.WithValue(ctx, correlationIDKey{}, correlationID)
+		ctx = context.WithValue(ctx, userIDKey{}, metadataValue(ctx, userIDMetadataKey))
+
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.SetAttributes(attribute.String("correlation.id", correlationID))
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// CorrelationIDFromContext extracts the correlation ID stashed by
+// UnaryCorrelationInterceptor, mirroring middleware.GetCorrelationID for
+// gRPC handlers.
+func CorrelationIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(correlationIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// UserIDFromContext extracts the user ID stashed by
+// UnaryCorrelationInterceptor, mirroring middleware.CartContext's userID
+// for gRPC handlers.
+func UserIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(userIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// UnaryLoggingInterceptor logs one line per RPC with the same fields
+// middleware.Logger logs for HTTP requests, so gRPC and HTTP traffic show up
+// the same way in log aggregation. Run it after UnaryCorrelationInterceptor
+// in the chain, since it reads the correlation and user IDs from the
+// context those interceptors populate.
+func UnaryLoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		traceID := ""
+		spanID := ""
+		if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+			traceID = span.SpanContext().TraceID().String()
+			spanID = span.SpanContext().SpanID().String()
+		}
+
+		logger.Info("gRPC Request",
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("correlationID", CorrelationIDFromContext(ctx)),
+			zap.String("traceID", traceID),
+			zap.String("spanID", spanID),
+			zap.String("userID", UserIDFromContext(ctx)),
+			zap.Time("timestamp", start),
+		)
+
+		return resp, err
+	}
+}
+
+// scopesKey is an unexported context key holding the scopes UnaryAuthInterceptor
+// extracted from the caller's bearer token.
+type scopesKey struct{}
+
+// UnaryAuthInterceptor validates the bearer token in the inbound "authorization"
+// metadata using the same middleware.TokenVerifier the HTTP layer's
+// AuthMiddleware uses, and replaces whatever user ID UnaryCorrelationInterceptor
+// picked up from the caller-supplied "x-user-id" metadata with the verified
+// token's subject, so a gRPC caller can't impersonate another user by setting
+// that header directly. Run it after UnaryCorrelationInterceptor in the chain.
+func UnaryAuthInterceptor(verifier middleware.TokenVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		authHeader := metadataValue(ctx, "authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata must be a bearer token")
+		}
+
+		claims, err := verifier.Verify(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid JWT token")
+		}
+
+		userID, _ := claims["sub"].(string)
+		if userID == "" {
+			userID, _ = claims["id"].(string)
+		}
+		if userID == "" {
+			return nil, status.Error(codes.Unauthenticated, "user ID not found in token")
+		}
+
+		ctx = context.WithValue(ctx, userIDKey{}, userID)
+		ctx = context.WithValue(ctx, scopesKey{}, extractScopes(claims))
+
+		return handler(ctx, req)
+	}
+}
+
+// extractScopes normalizes claims' scope claim into a []string, mirroring
+// middleware.extractScopes for the space-delimited "scope", "scp", and
+// "roles" claim shapes.
+func extractScopes(claims map[string]interface{}) []string {
+	if scope, ok := claims["scope"].(string); ok && scope != "" {
+		return strings.Fields(scope)
+	}
+
+	for _, key := range []string{"scp", "roles"} {
+		if raw, ok := claims[key].([]interface{}); ok {
+			scopes := make([]string, 0, len(raw))
+			for _, v := range raw {
+				if s, ok := v.(string); ok {
+					scopes = append(scopes, s)
+				}
+			}
+			if len(scopes) > 0 {
+				return scopes
+			}
+		}
+	}
+
+	return nil
+}
+
+// ScopesFromContext extracts the scopes UnaryAuthInterceptor stashed from the
+// caller's bearer token.
+func ScopesFromContext(ctx context.Context) []string {
+	if scopes, ok := ctx.Value(scopesKey{}).([]string); ok {
+		return scopes
+	}
+	return nil
+}
+
+// UnaryRecoveryInterceptor recovers a panic in any later interceptor or the
+// handler itself, logging it with a stack trace and returning an Internal
+// error instead of crashing the process, mirroring gin.Recovery() for the
+// HTTP server. Run it first in the chain so it can catch a panic anywhere
+// downstream.
+func UnaryRecoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("Recovered from panic in gRPC handler",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.String("stack", string(debug.Stack())))
+				err = status.Error(codes.Internal, fmt.Sprintf("internal error: %v", r))
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
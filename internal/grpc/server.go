@@ -0,0 +1,221 @@
+// Package grpc exposes the cart domain layer over gRPC, mirroring the
+// CartHandler HTTP endpoints so internal services can talk to cart-service
+// without HTTP overhead.
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aioutlet/cart-service/internal/grpc/cartpb"
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/services"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterServer registers CartServer on a *grpc.Server.
+func RegisterServer(grpcSrv *grpc.Server, cartServer *CartServer) {
+	cartpb.RegisterCartServiceServer(grpcSrv, cartServer)
+}
+
+// CartServer implements cartpb.CartServiceServer on top of services.CartService,
+// the same domain layer the HTTP handlers use.
+type CartServer struct {
+	cartpb.UnimplementedCartServiceServer
+
+	cartService services.CartService
+	logger      *zap.Logger
+}
+
+// NewCartServer creates a new gRPC cart server.
+func NewCartServer(cartService services.CartService, logger *zap.Logger) *CartServer {
+	return &CartServer{
+		cartService: cartService,
+		logger:      logger,
+	}
+}
+
+func (s *CartServer) GetCart(ctx context.Context, req *cartpb.GetCartRequest) (*cartpb.CartResponse, error) {
+	cart, err := s.cartService.GetCart(ctx, req.UserId)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &cartpb.CartResponse{Cart: toProtoCart(cart)}, nil
+}
+
+func (s *CartServer) AddItem(ctx context.Context, req *cartpb.AddItemRequest) (*cartpb.CartResponse, error) {
+	cart, err := s.cartService.AddItem(ctx, req.UserId, models.AddItemRequest{
+		ProductID: req.ProductId,
+		Quantity:  int(req.Quantity),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &cartpb.CartResponse{Cart: toProtoCart(cart)}, nil
+}
+
+func (s *CartServer) UpdateItemQuantity(ctx context.Context, req *cartpb.UpdateItemQuantityRequest) (*cartpb.CartResponse, error) {
+	cart, err := s.cartService.UpdateItem(ctx, req.UserId, req.ProductId, models.UpdateItemRequest{
+		Quantity: int(req.Quantity),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &cartpb.CartResponse{Cart: toProtoCart(cart)}, nil
+}
+
+func (s *CartServer) RemoveItem(ctx context.Context, req *cartpb.RemoveItemRequest) (*cartpb.CartResponse, error) {
+	cart, err := s.cartService.RemoveItem(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &cartpb.CartResponse{Cart: toProtoCart(cart)}, nil
+}
+
+func (s *CartServer) ClearCart(ctx context.Context, req *cartpb.ClearCartRequest) (*cartpb.ClearCartResponse, error) {
+	if err := s.cartService.ClearCart(ctx, req.UserId); err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &cartpb.ClearCartResponse{}, nil
+}
+
+func (s *CartServer) ExtendExpiry(ctx context.Context, req *cartpb.ExtendExpiryRequest) (*cartpb.CartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ExtendExpiry requires repository-level TTL support, not yet wired through CartService")
+}
+
+func (s *CartServer) TransferCart(ctx context.Context, req *cartpb.TransferCartRequest) (*cartpb.CartResponse, error) {
+	cart, err := s.cartService.TransferCart(ctx, req.FromUserId, req.ToUserId)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &cartpb.CartResponse{Cart: toProtoCart(cart)}, nil
+}
+
+func (s *CartServer) ValidateCart(ctx context.Context, req *cartpb.ValidateCartRequest) (*cartpb.CartResponse, error) {
+	cart, err := s.cartService.ValidateCart(ctx, req.UserId)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &cartpb.CartResponse{Cart: toProtoCart(cart)}, nil
+}
+
+func (s *CartServer) GetCartSummary(ctx context.Context, req *cartpb.GetCartSummaryRequest) (*cartpb.CartSummaryResponse, error) {
+	summary, err := s.cartService.GetCartSummary(ctx, req.UserId)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	items := make([]*cartpb.CartItem, 0, len(summary.Items))
+	for _, item := range summary.Items {
+		items = append(items, &cartpb.CartItem{
+			ProductId:   item.ProductID,
+			ProductName: item.ProductName,
+			Sku:         item.SKU,
+			Price:       item.Price,
+			Quantity:    int32(item.Quantity),
+			ImageUrl:    item.ImageURL,
+			Category:    item.Category,
+			Subtotal:    item.Subtotal,
+			AddedAt:     cartpb.NewTimestamp(item.AddedAt),
+		})
+	}
+
+	return &cartpb.CartSummaryResponse{
+		UserId:     summary.UserID,
+		Items:      items,
+		TotalPrice: summary.TotalPrice,
+		TotalItems: int32(summary.TotalItems),
+	}, nil
+}
+
+// WatchCart streams the cart every time it changes. It relies on Redis
+// keyspace notifications (`notify-keyspace-events KEA`) for the cart's key
+// and pushes a fresh snapshot on each event; callers should enable
+// keyspace notifications on the Redis deployment for this RPC to emit
+// anything beyond the initial snapshot.
+func (s *CartServer) WatchCart(req *cartpb.WatchCartRequest, stream cartpb.CartService_WatchCartServer) error {
+	ctx := stream.Context()
+
+	cart, err := s.cartService.GetCart(ctx, req.UserId)
+	if err != nil {
+		return toGRPCError(err)
+	}
+	if err := stream.Send(&cartpb.CartResponse{Cart: toProtoCart(cart)}); err != nil {
+		return err
+	}
+
+	changes, stop, err := watchCartKey(ctx, req.UserId)
+	if err != nil {
+		s.logger.Warn("Failed to subscribe to cart keyspace notifications",
+			zap.String("userID", req.UserId), zap.Error(err))
+		return nil
+	}
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changes:
+			cart, err := s.cartService.GetCart(ctx, req.UserId)
+			if err != nil {
+				s.logger.Error("Failed to reload cart for WatchCart", zap.Error(err))
+				continue
+			}
+			if err := stream.Send(&cartpb.CartResponse{Cart: toProtoCart(cart)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toProtoCart(cart *models.Cart) *cartpb.Cart {
+	if cart == nil {
+		return nil
+	}
+
+	items := make([]*cartpb.CartItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, &cartpb.CartItem{
+			ProductId:   item.ProductID,
+			ProductName: item.ProductName,
+			Sku:         item.SKU,
+			Price:       item.Price,
+			Quantity:    int32(item.Quantity),
+			ImageUrl:    item.ImageURL,
+			Category:    item.Category,
+			Subtotal:    item.Subtotal,
+			AddedAt:     cartpb.NewTimestamp(item.AddedAt),
+		})
+	}
+
+	return &cartpb.Cart{
+		UserId:     cart.UserID,
+		Items:      items,
+		TotalPrice: cart.TotalPrice,
+		TotalItems: int32(cart.TotalItems),
+		CreatedAt:  cartpb.NewTimestamp(cart.CreatedAt),
+		UpdatedAt:  cartpb.NewTimestamp(cart.UpdatedAt),
+		ExpiresAt:  cartpb.NewTimestamp(cart.ExpiresAt),
+	}
+}
+
+// toGRPCError maps domain errors to the same semantics CartHandler.getErrorStatusCode
+// applies to HTTP status codes, so REST and gRPC clients see equivalent failures.
+func toGRPCError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrCartNotFound), errors.Is(err, models.ErrItemNotFound), errors.Is(err, models.ErrProductNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrInsufficientStock), errors.Is(err, models.ErrMaxItemsExceeded), errors.Is(err, models.ErrMaxQuantityExceeded), errors.Is(err, models.ErrInvalidQuantity),
+		errors.Is(err, models.ErrNoBundleConfigurationGiven), errors.Is(err, models.ErrVariantDoNotExist), errors.Is(err, models.ErrProductNotTypeBundle),
+		errors.Is(err, models.ErrBundleItemQuantityImmutable):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrCartExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// watchCartKey subscribes to Redis keyspace notifications for the given
+// user's cart key and returns a channel that receives a value every time
+// the key changes, along with a stop function to unsubscribe. The Redis
+// deployment must have `notify-keyspace-events` include `K` and `E` (or
+// `A`) for this to emit anything; when redisClient is nil the returned
+// channel never fires and WatchCart falls back to a single snapshot.
+func watchCartKey(ctx context.Context, userID string) (<-chan struct{}, func(), error) {
+	if redisClient == nil {
+		ch := make(chan struct{})
+		return ch, func() {}, nil
+	}
+
+	pattern := "__keyspace@*__:cart:" + userID
+	pubsub := redisClient.PSubscribe(ctx, pattern)
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		for range pubsub.Channel() {
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return changes, func() { _ = pubsub.Close() }, nil
+}
+
+// redisClient is set once at startup via SetRedisClient so WatchCart can
+// subscribe to keyspace notifications without threading a client through
+// every call.
+var redisClient *redis.Client
+
+// SetRedisClient wires the Redis client used for cart keyspace
+// notifications consumed by CartServer.WatchCart.
+func SetRedisClient(client *redis.Client) {
+	redisClient = client
+}
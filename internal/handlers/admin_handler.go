@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/purge"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminHandler handles operational endpoints restricted to the cart:admin scope.
+type AdminHandler struct {
+	sweeper *purge.Sweeper
+	logger  *zap.Logger
+}
+
+// NewAdminHandler creates a new admin handler.
+func NewAdminHandler(sweeper *purge.Sweeper, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		sweeper: sweeper,
+		logger:  logger,
+	}
+}
+
+// PurgeCarts godoc
+// @Summary Purge lapsed carts
+// @Description Scan the state store for expired carts and delete them, outside the sweeper's regular interval
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param scope query string true "What to purge; only 'expired' is supported"
+// @Success 200 {object} models.PurgeResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/carts/purge [post]
+func (h *AdminHandler) PurgeCarts(c *gin.Context) {
+	scope := c.Query("scope")
+	if scope != "expired" {
+		h.respondWithError(c, http.StatusBadRequest, "Unsupported purge scope, only 'expired' is supported", nil)
+		return
+	}
+
+	result, err := h.sweeper.Sweep(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Admin-triggered purge sweep failed", zap.Error(err))
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to purge expired carts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PurgeResponse{
+		Success: true,
+		Message: "Purge sweep complete",
+		Data: &models.PurgeResult{
+			Scanned: result.Scanned,
+			Deleted: result.Deleted,
+		},
+	})
+}
+
+func (h *AdminHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	response := models.ErrorResponse{
+		Success: false,
+		Message: message,
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	c.JSON(statusCode, response)
+}
@@ -268,6 +268,48 @@ func (h *CartHandler) TransferCart(c *gin.Context) {
 	h.respondWithSuccess(c, http.StatusOK, "Cart transferred successfully", cart)
 }
 
+// BulkApply godoc
+// @Summary Apply bulk cart operations
+// @Description Apply a batch of add/update/remove operations to the user's cart in one round-trip
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BulkApplyRequest true "Bulk apply request"
+// @Success 200 {object} models.BulkApplyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /cart/items:bulk [post]
+func (h *CartHandler) BulkApply(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		h.respondWithError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	var request models.BulkApplyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	cart, results, err := h.cartService.BulkApply(c.Request.Context(), userID.(string), request.Ops)
+	if err != nil {
+		h.logger.Error("Failed to apply bulk cart operations",
+			zap.String("userID", userID.(string)),
+			zap.Error(err))
+		h.respondWithError(c, h.getErrorStatusCode(err), err.Error(), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BulkApplyResponse{
+		Success: true,
+		Message: "Bulk cart operations applied",
+		Data:    &models.BulkApplyResult{Cart: cart, Results: results},
+	})
+}
+
 // Guest cart handlers (no authentication required)
 
 // GetGuestCart godoc
@@ -459,6 +501,47 @@ func (h *CartHandler) ClearGuestCart(c *gin.Context) {
 	})
 }
 
+// BulkApplyGuest godoc
+// @Summary Apply bulk operations to guest cart
+// @Description Apply a batch of add/update/remove operations to a guest user's cart in one round-trip
+// @Tags Guest Cart
+// @Accept json
+// @Produce json
+// @Param guestId path string true "Guest ID"
+// @Param request body models.BulkApplyRequest true "Bulk apply request"
+// @Success 200 {object} models.BulkApplyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /guest/cart/{guestId}/items:bulk [post]
+func (h *CartHandler) BulkApplyGuest(c *gin.Context) {
+	guestID := c.Param("guestId")
+	if guestID == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Guest ID is required", nil)
+		return
+	}
+
+	var request models.BulkApplyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	cart, results, err := h.cartService.BulkApply(c.Request.Context(), guestID, request.Ops)
+	if err != nil {
+		h.logger.Error("Failed to apply bulk cart operations to guest cart",
+			zap.String("guestID", guestID),
+			zap.Error(err))
+		h.respondWithError(c, h.getErrorStatusCode(err), err.Error(), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BulkApplyResponse{
+		Success: true,
+		Message: "Bulk cart operations applied",
+		Data:    &models.BulkApplyResult{Cart: cart, Results: results},
+	})
+}
+
 // Helper methods
 
 func (h *CartHandler) respondWithSuccess(c *gin.Context, statusCode int, message string, data interface{}) {
@@ -510,7 +593,9 @@ func (h *CartHandler) getErrorStatusCode(err error) int {
 	switch err {
 	case models.ErrCartNotFound, models.ErrItemNotFound, models.ErrProductNotFound:
 		return http.StatusNotFound
-	case models.ErrInsufficientStock, models.ErrMaxItemsExceeded, models.ErrMaxQuantityExceeded, models.ErrInvalidQuantity:
+	case models.ErrInsufficientStock, models.ErrMaxItemsExceeded, models.ErrMaxQuantityExceeded, models.ErrInvalidQuantity,
+		models.ErrNoBundleConfigurationGiven, models.ErrVariantDoNotExist, models.ErrProductNotTypeBundle,
+		models.ErrBundleItemQuantityImmutable:
 		return http.StatusBadRequest
 	case models.ErrCartExpired:
 		return http.StatusGone
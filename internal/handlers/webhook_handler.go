@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/webhooks"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WebhookHandler handles CRUD of a caller's webhook subscriptions
+type WebhookHandler struct {
+	store  webhooks.SubscriptionStore
+	logger *zap.Logger
+}
+
+// NewWebhookHandler creates a new webhook subscription handler
+func NewWebhookHandler(store webhooks.SubscriptionStore, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// CreateSubscription godoc
+// @Summary Create a webhook subscription
+// @Description Register a URL to receive cart lifecycle events
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateWebhookSubscriptionRequest true "Create subscription request"
+// @Success 201 {object} models.WebhookSubscriptionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		h.respondWithError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	var request models.CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	sub, err := h.store.Create(c.Request.Context(), userID.(string), request)
+	if err != nil {
+		h.logger.Error("Failed to create webhook subscription",
+			zap.String("userID", userID.(string)),
+			zap.Error(err))
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to create webhook subscription", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.WebhookSubscriptionResponse{
+		Success: true,
+		Message: "Webhook subscription created successfully",
+		Data:    sub,
+	})
+}
+
+// ListSubscriptions godoc
+// @Summary List webhook subscriptions
+// @Description List the caller's registered webhook subscriptions
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.WebhookSubscriptionListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks/subscriptions [get]
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		h.respondWithError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	subs, err := h.store.List(c.Request.Context(), userID.(string))
+	if err != nil {
+		h.logger.Error("Failed to list webhook subscriptions",
+			zap.String("userID", userID.(string)),
+			zap.Error(err))
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to list webhook subscriptions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebhookSubscriptionListResponse{
+		Success: true,
+		Message: "Webhook subscriptions retrieved successfully",
+		Data:    subs,
+	})
+}
+
+// UpdateSubscription godoc
+// @Summary Update a webhook subscription
+// @Description Update the URL, secret, or event filter of a webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param subscriptionId path string true "Subscription ID"
+// @Param request body models.UpdateWebhookSubscriptionRequest true "Update subscription request"
+// @Success 200 {object} models.WebhookSubscriptionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks/subscriptions/{subscriptionId} [put]
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		h.respondWithError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	subscriptionID := c.Param("subscriptionId")
+	if subscriptionID == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Subscription ID is required", nil)
+		return
+	}
+
+	var request models.UpdateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	sub, err := h.store.Update(c.Request.Context(), userID.(string), subscriptionID, request)
+	if err != nil {
+		h.logger.Error("Failed to update webhook subscription",
+			zap.String("userID", userID.(string)),
+			zap.String("subscriptionID", subscriptionID),
+			zap.Error(err))
+		h.respondWithError(c, h.getErrorStatusCode(err), err.Error(), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebhookSubscriptionResponse{
+		Success: true,
+		Message: "Webhook subscription updated successfully",
+		Data:    sub,
+	})
+}
+
+// DeleteSubscription godoc
+// @Summary Delete a webhook subscription
+// @Description Remove a registered webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param subscriptionId path string true "Subscription ID"
+// @Success 200 {object} models.WebhookSubscriptionResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks/subscriptions/{subscriptionId} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		h.respondWithError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	subscriptionID := c.Param("subscriptionId")
+	if subscriptionID == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Subscription ID is required", nil)
+		return
+	}
+
+	if err := h.store.Delete(c.Request.Context(), userID.(string), subscriptionID); err != nil {
+		h.logger.Error("Failed to delete webhook subscription",
+			zap.String("userID", userID.(string)),
+			zap.String("subscriptionID", subscriptionID),
+			zap.Error(err))
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to delete webhook subscription", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Webhook subscription deleted successfully",
+	})
+}
+
+// Helper methods
+
+func (h *WebhookHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	response := models.ErrorResponse{
+		Success: false,
+		Message: message,
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	c.JSON(statusCode, response)
+}
+
+func (h *WebhookHandler) getErrorStatusCode(err error) int {
+	switch err {
+	case models.ErrWebhookSubscriptionNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,27 +1,90 @@
 package middleware
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"strings"
-	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
-	
+
+	"github.com/aioutlet/cart-service/pkg/jwks"
 	"github.com/aioutlet/cart-service/pkg/secrets"
 )
 
-var (
-	jwtSecretCache string
-	jwtSecretMutex sync.RWMutex
-)
+// TokenVerifier parses and validates a raw bearer token, returning its
+// claims. AuthMiddleware and OptionalAuthMiddleware are agnostic to which
+// signing scheme is in play: HMACVerifier covers the legacy shared-secret
+// path, JWKSVerifier covers RS256/ES256 tokens issued by an OIDC provider.
+type TokenVerifier interface {
+	Verify(tokenString string) (jwt.MapClaims, error)
+}
+
+// HMACVerifier validates HS256 tokens against the secret(s) held by a
+// Rotator, accepting the previous secret too during a rotation window.
+type HMACVerifier struct {
+	Rotator *secrets.Rotator
+}
 
-// AuthMiddleware validates JWT tokens and extracts user information
-// Loads JWT secret from Dapr Secret Store on first use (lazy loading)
-func AuthMiddleware(secretManager *secrets.DaprSecretManager, logger *zap.Logger) gin.HandlerFunc {
+// Verify implements TokenVerifier.
+func (v *HMACVerifier) Verify(tokenString string) (jwt.MapClaims, error) {
+	var lastErr error
+	for _, secret := range v.Rotator.Secrets() {
+		secret := secret
+		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return []byte(secret), nil
+		})
+		if err == nil && token.Valid {
+			return claimsOf(token)
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// JWKSVerifier validates RS256/ES256 tokens against a KeySet discovered
+// from an OIDC issuer's JWKS endpoint, looking the signing key up by the
+// token's "kid" header.
+type JWKSVerifier struct {
+	KeySet *jwks.KeySet
+}
+
+// Verify implements TokenVerifier.
+func (v *JWKSVerifier) Verify(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := v.KeySet.Key(kid)
+		if !ok {
+			return nil, fmt.Errorf("no matching JWKS key for kid '%s'", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimsOf(token)
+}
+
+func claimsOf(token *jwt.Token) (jwt.MapClaims, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid JWT token claims")
+	}
+	return claims, nil
+}
+
+// AuthMiddleware validates JWT tokens and extracts user information.
+func AuthMiddleware(verifier TokenVerifier, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -54,27 +117,7 @@ func AuthMiddleware(secretManager *secrets.DaprSecretManager, logger *zap.Logger
 			return
 		}
 
-		// Get JWT secret with lazy loading from Dapr
-		secretKey, err := getJWTSecret(c.Request.Context(), secretManager, logger)
-		if err != nil {
-			logger.Error("Failed to get JWT secret", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "Failed to load JWT configuration",
-			})
-			c.Abort()
-			return
-		}
-
-		// Parse and validate the token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validate the signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(secretKey), nil
-		})
-
+		claims, err := verifier.Verify(tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
@@ -85,54 +128,28 @@ func AuthMiddleware(secretManager *secrets.DaprSecretManager, logger *zap.Logger
 			return
 		}
 
-		// Check if token is valid and extract claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			// Extract user ID from claims - try 'sub' first, then 'id'
-			var userID string
-			if sub, exists := claims["sub"].(string); exists {
-				userID = sub
-			} else if id, exists := claims["id"].(string); exists {
-				userID = id
-			} else {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"success": false,
-					"message": "User ID not found in token",
-				})
-				c.Abort()
-				return
-			}
-
-			// Set user information in context
-			c.Set("userID", userID)
-			
-			// Extract additional claims if available
-			if email, exists := claims["email"].(string); exists {
-				c.Set("userEmail", email)
-			}
-			
-			if role, exists := claims["role"].(string); exists {
-				c.Set("userRole", role)
-			}
-			
-			if username, exists := claims["username"].(string); exists {
-				c.Set("username", username)
-			}
-
-			c.Next()
+		// Extract user ID from claims - try 'sub' first, then 'id'
+		var userID string
+		if sub, exists := claims["sub"].(string); exists {
+			userID = sub
+		} else if id, exists := claims["id"].(string); exists {
+			userID = id
 		} else {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
-				"message": "Invalid JWT token claims",
+				"message": "User ID not found in token",
 			})
 			c.Abort()
 			return
 		}
+
+		setClaims(c, userID, claims)
+		c.Next()
 	}
 }
 
-// OptionalAuthMiddleware validates JWT tokens if present but doesn't require them
-// Loads JWT secret from Dapr Secret Store on first use (lazy loading)
-func OptionalAuthMiddleware(secretManager *secrets.DaprSecretManager, logger *zap.Logger) gin.HandlerFunc {
+// OptionalAuthMiddleware validates JWT tokens if present but doesn't require them.
+func OptionalAuthMiddleware(verifier TokenVerifier, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -153,80 +170,70 @@ func OptionalAuthMiddleware(secretManager *secrets.DaprSecretManager, logger *za
 			return
 		}
 
-		// Get JWT secret with lazy loading from Dapr
-		secretKey, err := getJWTSecret(c.Request.Context(), secretManager, logger)
+		claims, err := verifier.Verify(tokenString)
 		if err != nil {
-			logger.Warn("Failed to get JWT secret for optional auth", zap.Error(err))
 			c.Next()
 			return
 		}
 
-		// Parse and validate the token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validate the signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(secretKey), nil
-		})
-
-		if err != nil {
-			c.Next()
-			return
-		}
-
-		// Check if token is valid and extract claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			// Extract user ID from claims - try 'sub' first, then 'id'
-			if userID, exists := claims["sub"].(string); exists {
-				c.Set("userID", userID)
-			} else if userID, exists := claims["id"].(string); exists {
-				c.Set("userID", userID)
-			}
-			
-			// Extract additional claims if available
-			if email, exists := claims["email"].(string); exists {
-				c.Set("userEmail", email)
-			}
-			
-			if role, exists := claims["role"].(string); exists {
-				c.Set("userRole", role)
-			}
-			
-			if username, exists := claims["username"].(string); exists {
-				c.Set("username", username)
-			}
+		var userID string
+		if sub, exists := claims["sub"].(string); exists {
+			userID = sub
+		} else if id, exists := claims["id"].(string); exists {
+			userID = id
 		}
 
+		setClaims(c, userID, claims)
 		c.Next()
 	}
 }
 
-// getJWTSecret retrieves JWT secret with caching
-func getJWTSecret(ctx context.Context, secretManager *secrets.DaprSecretManager, logger *zap.Logger) (string, error) {
-	// Check cache first (read lock)
-	jwtSecretMutex.RLock()
-	if jwtSecretCache != "" {
-		defer jwtSecretMutex.RUnlock()
-		return jwtSecretCache, nil
+// setClaims populates the gin context with the fields RequireScope and
+// downstream handlers read: userID/userEmail/userRole/username for
+// backward compatibility, plus the normalized "scopes" list RequireScope
+// checks against.
+func setClaims(c *gin.Context, userID string, claims jwt.MapClaims) {
+	if userID != "" {
+		c.Set("userID", userID)
 	}
-	jwtSecretMutex.RUnlock()
 
-	// Load from Dapr (write lock)
-	jwtSecretMutex.Lock()
-	defer jwtSecretMutex.Unlock()
+	if email, exists := claims["email"].(string); exists {
+		c.Set("userEmail", email)
+	}
 
-	// Double-check after acquiring write lock
-	if jwtSecretCache != "" {
-		return jwtSecretCache, nil
+	if role, exists := claims["role"].(string); exists {
+		c.Set("userRole", role)
 	}
 
-	// Load from Dapr Secret Store
-	secret, err := secretManager.GetJWTSecret(ctx)
-	if err != nil {
-		return "", err
+	if username, exists := claims["username"].(string); exists {
+		c.Set("username", username)
+	}
+
+	c.Set("scopes", extractScopes(claims))
+}
+
+// extractScopes normalizes the token's scope claim into a []string,
+// supporting the three shapes OIDC providers commonly use: a
+// space-delimited "scope" string (OAuth2), a "scp" array (e.g. Okta), or a
+// "roles" array (e.g. Auth0 custom claims).
+func extractScopes(claims jwt.MapClaims) []string {
+	if scope, ok := claims["scope"].(string); ok && scope != "" {
+		return strings.Fields(scope)
+	}
+
+	for _, key := range []string{"scp", "roles"} {
+		if raw, ok := claims[key].([]interface{}); ok {
+			scopes := make([]string, 0, len(raw))
+			for _, v := range raw {
+				if s, ok := v.(string); ok {
+					scopes = append(scopes, s)
+				}
+			}
+			if len(scopes) > 0 {
+				return scopes
+			}
+		}
 	}
 
-	jwtSecretCache = secret
-	return secret, nil
+	return nil
 }
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scope authorization, modeled on micro's auth package: each route group
+// declares the scope required to reach it. ScopePublic lets any request
+// through, including unauthenticated ones; ScopeAccount only requires a
+// valid account (any authenticated user, no specific scope); named scopes
+// like "cart:write" require that exact scope on the token.
+const (
+	ScopePublic  = ""
+	ScopeAccount = "*"
+)
+
+// ErrForbidden is the message returned when an authenticated request's
+// token lacks the scope a route requires.
+const ErrForbidden = "insufficient scope"
+
+// Rule associates a scope requirement with the resource it guards. It's a
+// declarative counterpart to RequireScope, useful for building a route
+// table or authorization audit rather than enforcing anything itself.
+type Rule struct {
+	Resource string
+	Scope    string
+}
+
+// RequireScope returns gin middleware that 403s with ErrForbidden unless
+// the authenticated request's token carries scope. Must run after
+// AuthMiddleware (or OptionalAuthMiddleware, for ScopeAccount/ScopePublic
+// routes that only sometimes require auth) so "userID" and "scopes" are
+// already set in the context.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scope == ScopePublic {
+			c.Next()
+			return
+		}
+
+		userID, _ := c.Get("userID")
+		if id, ok := userID.(string); !ok || id == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "Authentication required",
+			})
+			c.Abort()
+			return
+		}
+
+		if scope == ScopeAccount {
+			c.Next()
+			return
+		}
+
+		if !hasScope(c, scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": ErrForbidden,
+				"scope":   scope,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hasScope reports whether the "scopes" AuthMiddleware extracted from the
+// token's scope/scp/roles claim include scope.
+func hasScope(c *gin.Context, scope string) bool {
+	raw, exists := c.Get("scopes")
+	if !exists {
+		return false
+	}
+
+	scopes, ok := raw.([]string)
+	if !ok {
+		return false
+	}
+
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
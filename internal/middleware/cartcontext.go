@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/aioutlet/cart-service/internal/cartctx"
+)
+
+// CartContext middleware reads cart/session/experiment context from inbound
+// headers, attaches it to the request context as OTEL baggage so it survives
+// any downstream Dapr/gRPC hop, and stamps it onto the request's active span.
+// It must run after AuthMiddleware/OptionalAuthMiddleware so "userID" is
+// already set in the Gin context.
+func CartContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cc := cartctx.CartContext{
+			SessionID:  c.GetHeader("X-Cart-Session-Id"),
+			Currency:   c.GetHeader("X-Cart-Currency"),
+			Experiment: c.GetHeader("X-Pricing-Experiment"),
+		}
+		if userID, exists := c.Get("userID"); exists {
+			if id, ok := userID.(string); ok {
+				cc.UserID = id
+			}
+		}
+
+		ctx, err := cartctx.WithBaggage(c.Request.Context(), cc)
+		if err == nil {
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		cartctx.StampSpan(c.Request.Context())
+
+		c.Next()
+	}
+}
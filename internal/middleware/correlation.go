@@ -1,12 +1,19 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// correlationIDContextKey is an unexported context key, mirroring how
+// internal/grpc/interceptor.go stashes the correlation ID on a gRPC call's
+// context so it survives a c.Request.Context() handoff into the service layer.
+type correlationIDContextKey struct{}
+
 // CorrelationID middleware adds correlation ID to requests for distributed tracing
 func CorrelationID() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -18,7 +25,12 @@ func CorrelationID() gin.HandlerFunc {
 
 		// Set correlation ID in context
 		c.Set("correlationID", correlationID)
-		
+
+		// Stash it on the request context too, so code below the handler
+		// (services, repositories) can read it without a *gin.Context.
+		ctx := context.WithValue(c.Request.Context(), correlationIDContextKey{}, correlationID)
+		c.Request = c.Request.WithContext(ctx)
+
 		// Add correlation ID to response header
 		c.Header("X-Correlation-ID", correlationID)
 
@@ -31,6 +43,16 @@ func CorrelationID() gin.HandlerFunc {
 	}
 }
 
+// CorrelationIDFromContext extracts the correlation ID stashed by
+// CorrelationID, returning "" if none is present (e.g. outside an HTTP
+// request, or in tests that don't run the middleware).
+func CorrelationIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(correlationIDContextKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
 // GetCorrelationID extracts correlation ID from Gin context
 func GetCorrelationID(c *gin.Context) string {
 	if correlationID, exists := c.Get("correlationID"); exists {
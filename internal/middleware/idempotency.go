@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goredis "github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// DefaultIdempotencyTTL is how long a cached idempotent response is kept
+// when the caller doesn't configure one.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+const (
+	idempotencyKeyPrefix = "idempotency:"
+	idempotencyLockTTL   = 5 * time.Second
+	// idempotencyLockWait is how long a request that lost the SetNX race
+	// waits for the winner to publish its cached response. It matches
+	// idempotencyLockTTL so a loser waits out the winner's entire possible
+	// hold time rather than giving up early and re-executing the handler
+	// concurrently with it.
+	idempotencyLockWait  = idempotencyLockTTL
+	idempotencyPollEvery = 100 * time.Millisecond
+)
+
+// idempotentResponse is what's cached in Redis for a given Idempotency-Key.
+type idempotentResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Body       []byte `json:"body"`
+	BodyHash   string `json:"bodyHash"`
+}
+
+// Idempotency middleware dedupes retried mutation requests that carry an
+// `Idempotency-Key` header: on first request it executes the handler and
+// caches the resulting status + body in Redis under a hash of
+// {identity, method, path, key, body}; later requests with the same key
+// replay the cached response verbatim. A matching key with a different
+// request body is rejected with 422, since that means the client reused a
+// key for a different operation. Requests without the header pass through
+// unchanged.
+func Idempotency(redisClient *goredis.Client, ttl time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		bodyBytes, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logger.Warn("Failed to read request body for idempotency check", zap.Error(err))
+			c.Next()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+
+		ctx := c.Request.Context()
+		redisKey := idempotencyRedisKey(c, key)
+		bodyHash := sha256Hex(bodyBytes)
+
+		if cached, ok := waitForCachedResponse(ctx, redisClient, redisKey, 0, logger); ok {
+			replayCached(c, cached, bodyHash)
+			return
+		}
+
+		lockKey := redisKey + ":lock"
+		acquired, err := redisClient.SetNX(ctx, lockKey, "1", idempotencyLockTTL).Result()
+		if err != nil {
+			logger.Warn("Failed to acquire idempotency lock, proceeding without dedup", zap.Error(err))
+			c.Next()
+			return
+		}
+		if !acquired {
+			// Lost the race to a concurrent request with the same key; wait for
+			// it to publish its result rather than double-executing the handler.
+			if cached, ok := waitForCachedResponse(ctx, redisClient, redisKey, idempotencyLockWait, logger); ok {
+				replayCached(c, cached, bodyHash)
+				return
+			}
+			// The winner still hasn't published a result after waiting out its
+			// entire possible lock hold time - it may be stuck or unusually
+			// slow. Falling through to c.Next() here would execute the handler
+			// concurrently with whatever the winner is still doing, which is
+			// exactly the double-execution this middleware exists to prevent,
+			// so reject instead of guessing.
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": "A request with this Idempotency-Key is still being processed",
+			})
+			return
+		}
+		defer redisClient.Del(ctx, lockKey)
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		c.Next()
+
+		resp := idempotentResponse{
+			StatusCode: recorder.statusCode(),
+			Body:       recorder.body.Bytes(),
+			BodyHash:   bodyHash,
+		}
+		data, err := json.Marshal(resp)
+		if err != nil {
+			logger.Warn("Failed to marshal idempotent response for caching", zap.Error(err))
+			return
+		}
+		if err := redisClient.Set(ctx, redisKey, data, ttl).Err(); err != nil {
+			logger.Warn("Failed to cache idempotent response", zap.String("idempotencyKey", key), zap.Error(err))
+		}
+	}
+}
+
+// waitForCachedResponse polls Redis for up to wait for a cached response
+// under redisKey, so a concurrent retry blocks on an in-flight request's
+// lock instead of re-executing the handler. A zero wait checks once.
+func waitForCachedResponse(ctx context.Context, redisClient *goredis.Client, redisKey string, wait time.Duration, logger *zap.Logger) (*idempotentResponse, bool) {
+	deadline := time.Now().Add(wait)
+	for {
+		data, err := redisClient.Get(ctx, redisKey).Bytes()
+		if err == nil {
+			var cached idempotentResponse
+			if err := json.Unmarshal(data, &cached); err != nil {
+				logger.Warn("Failed to unmarshal cached idempotent response", zap.Error(err))
+				return nil, false
+			}
+			return &cached, true
+		}
+		if err != goredis.Nil || time.Now().After(deadline) {
+			return nil, false
+		}
+		time.Sleep(idempotencyPollEvery)
+	}
+}
+
+// replayCached writes out a previously cached response, or a 422 if the
+// caller reused the idempotency key for a request with a different body.
+func replayCached(c *gin.Context, cached *idempotentResponse, bodyHash string) {
+	if cached.BodyHash != bodyHash {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"message": "Idempotency-Key reused with different payload",
+		})
+		return
+	}
+	c.Data(cached.StatusCode, gin.MIMEJSON, cached.Body)
+	c.Abort()
+}
+
+// idempotencyRedisKey scopes the cache key to the caller (authenticated
+// user or guest) as well as method/path/key, so two different callers can't
+// collide on the same Idempotency-Key value.
+func idempotencyRedisKey(c *gin.Context, key string) string {
+	identity := "anonymous"
+	if userID, exists := c.Get("userID"); exists {
+		if id, ok := userID.(string); ok {
+			identity = id
+		}
+	} else if guestID := c.Param("guestId"); guestID != "" {
+		identity = guestID
+	}
+
+	return fmt.Sprintf("%s%s:%s:%s:%s", idempotencyKeyPrefix, identity, c.Request.Method, c.FullPath(), key)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// idempotencyRecorder captures the status code and body the wrapped handler
+// writes, so Idempotency can cache them after c.Next() returns.
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body       *bytes.Buffer
+	statusOnce int
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *idempotencyRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+func (r *idempotencyRecorder) WriteHeader(statusCode int) {
+	r.statusOnce = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *idempotencyRecorder) statusCode() int {
+	if r.statusOnce != 0 {
+		return r.statusOnce
+	}
+	return r.ResponseWriter.Status()
+}
@@ -8,7 +8,11 @@ import (
 	"go.uber.org/zap"
 )
 
-// Logger middleware provides structured logging for HTTP requests
+// Logger middleware provides structured logging for HTTP requests. When
+// logger was built with logger.NewWithOTLP, these fields (including
+// correlationID/traceID/spanID) are also emitted as an OTLP log record
+// alongside the stdout line, with no change needed here — the fan-out
+// happens in the zap Core itself.
 func Logger(logger *zap.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 		correlationID := ""
@@ -56,7 +60,9 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 	})
 }
 
-// ErrorLogger middleware logs errors with correlation, trace, and span IDs
+// ErrorLogger middleware logs errors with correlation, trace, and span IDs.
+// Like Logger, it reaches the OTLP log backend too when logger came from
+// logger.NewWithOTLP.
 func ErrorLogger(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/aioutlet/cart-service/pkg/tracing"
+)
+
+// Tracing extracts a remote span context from the request's W3C traceparent
+// and tracestate headers (see tracing.InitTracing's global propagator) and
+// starts a server span per request, so every request is traced end to end
+// even when the caller already has a trace in flight. Register it ahead of
+// CorrelationID, which tags the active span with the correlation ID for
+// log-trace joining.
+func Tracing(serviceName string) gin.HandlerFunc {
+	tracer := tracing.GetTracer()
+
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		spanName := c.Request.Method + " " + c.FullPath()
+		ctx, span := tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethod(c.Request.Method),
+				semconv.HTTPTarget(c.Request.URL.Path),
+				semconv.HTTPRoute(c.FullPath()),
+				semconv.NetHostName(serviceName),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, "")
+		}
+		if len(c.Errors) > 0 {
+			span.SetAttributes(attribute.String("http.errors", c.Errors.String()))
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+// PurgeResult reports how many expired carts an admin purge sweep scanned
+// and deleted.
+type PurgeResult struct {
+	Scanned int `json:"scanned"`
+	Deleted int `json:"deleted"`
+}
+
+// PurgeResponse represents the response format for the admin cart purge endpoint.
+type PurgeResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    *PurgeResult `json:"data,omitempty"`
+}
@@ -27,12 +27,41 @@ type CartItem struct {
 	Category    string  `json:"category" redis:"category"`
 	Subtotal    float64 `json:"subtotal" redis:"subtotal"`
 	AddedAt     time.Time `json:"addedAt" redis:"added_at"`
+	// ReservationID correlates this item with the inventory stock
+	// reservation the AddItem saga made for it (see pkg/saga), so a
+	// recovered/compensated reservation can be traced back to the cart
+	// item it was for. Empty for items added before this field existed.
+	ReservationID string `json:"reservationId,omitempty" redis:"reservation_id"`
+	// BundleParentID links a child CartItem created from a request's
+	// BundleConfiguration back to the other items its bundle produced, so
+	// RemoveItem/UpdateItem can treat them as one unit. Empty for items
+	// that weren't added as part of a bundle.
+	BundleParentID string `json:"bundleParentId,omitempty" redis:"bundle_parent_id"`
 }
 
 // AddItemRequest represents a request to add an item to cart
 type AddItemRequest struct {
 	ProductID string `json:"productId" binding:"required"`
 	Quantity  int    `json:"quantity" binding:"required,min=1"`
+	// VariantCode optionally selects a configurable variant of ProductID
+	// (e.g. a chosen size/color). cartService.AddItem resolves it against
+	// ProductClient.GetProduct before pricing, using the same
+	// base-SKU-plus-suffix convention as pkg/utils.GenerateVariantSKU.
+	VariantCode string `json:"variantCode,omitempty"`
+	// BundleConfiguration selects the child products making up a bundle
+	// ProductID, for products whose ProductInfo.IsBundle is true. Required
+	// (and only valid) for bundle products; cartService.AddItem expands it
+	// into one linked CartItem per choice.
+	BundleConfiguration []BundleChoice `json:"bundleConfiguration,omitempty" binding:"omitempty,dive"`
+}
+
+// BundleChoice selects one child product for a bundle ProductID's
+// BundleConfiguration, e.g. IdentifierGroup "color" resolving to
+// MarketplaceCode "SHIRT-RED".
+type BundleChoice struct {
+	IdentifierGroup string `json:"identifierGroup" binding:"required"`
+	MarketplaceCode string `json:"marketplaceCode" binding:"required"`
+	Qty             int    `json:"qty" binding:"required,min=1"`
 }
 
 // UpdateItemRequest represents a request to update an item in cart
@@ -45,6 +74,48 @@ type TransferCartRequest struct {
 	GuestID string `json:"guestId" binding:"required"`
 }
 
+// CartOpType identifies what a single CartOp does to the cart.
+type CartOpType string
+
+const (
+	CartOpAdd    CartOpType = "add"
+	CartOpUpdate CartOpType = "update"
+	CartOpRemove CartOpType = "remove"
+)
+
+// CartOp is one operation in a BulkApplyRequest.
+type CartOp struct {
+	Type      CartOpType `json:"type" binding:"required,oneof=add update remove"`
+	ProductID string     `json:"productId" binding:"required"`
+	Quantity  int        `json:"quantity"`
+}
+
+// BulkApplyRequest represents a request to apply several cart operations
+// against a single loaded cart in one round-trip.
+type BulkApplyRequest struct {
+	Ops []CartOp `json:"ops" binding:"required,min=1,dive"`
+}
+
+// OpResult is the outcome of one CartOp within a BulkApply call.
+type OpResult struct {
+	ProductID string `json:"productId"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkApplyResult pairs the final cart with the per-op outcomes.
+type BulkApplyResult struct {
+	Cart    *Cart      `json:"cart"`
+	Results []OpResult `json:"results"`
+}
+
+// BulkApplyResponse represents the response format for bulk cart operations
+type BulkApplyResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    *BulkApplyResult `json:"data,omitempty"`
+}
+
 // ProductInfo represents product information from product service
 type ProductInfo struct {
 	ID          string  `json:"id"`
@@ -55,6 +126,9 @@ type ProductInfo struct {
 	Category    string  `json:"category"`
 	IsActive    bool    `json:"isActive"`
 	StockQty    int     `json:"stockQty"`
+	// IsBundle marks a product that must be added via BundleConfiguration
+	// instead of a plain quantity.
+	IsBundle bool `json:"isBundle"`
 }
 
 // CartResponse represents the response format for cart operations
@@ -89,6 +163,12 @@ var (
 	ErrMaxQuantityExceeded = errors.New("maximum quantity per item exceeded")
 	ErrInvalidQuantity  = errors.New("invalid quantity")
 	ErrCartExpired      = errors.New("cart has expired")
+	ErrCartConflict     = errors.New("cart was modified concurrently, retry")
+	ErrLockTokenMismatch = errors.New("cart lock token no longer matches the current holder")
+	ErrNoBundleConfigurationGiven = errors.New("bundle configuration is required for this product")
+	ErrVariantDoNotExist = errors.New("requested variant does not exist for this product")
+	ErrProductNotTypeBundle = errors.New("bundle configuration given for a product that is not a bundle")
+	ErrBundleItemQuantityImmutable = errors.New("bundle items cannot have their quantity changed individually; remove and re-add the bundle instead")
 )
 
 // NewCart creates a new cart for a user
@@ -165,12 +245,20 @@ func (c *Cart) UpdateItemQuantity(productID string, quantity int, maxQuantity in
 	for i, item := range c.Items {
 		if item.ProductID == productID {
 			if quantity == 0 {
-				// Remove item if quantity is 0
-				c.Items = append(c.Items[:i], c.Items[i+1:]...)
-			} else {
-				c.Items[i].Quantity = quantity
-				c.Items[i].Subtotal = float64(quantity) * item.Price
+				// Removing a bundle item removes the whole bundle, not just
+				// this one, so delegate to RemoveItem's cascade below.
+				return c.RemoveItem(productID)
+			}
+			if item.BundleParentID != "" {
+				// A bundle's child quantities were fixed by its
+				// BundleConfiguration (see resolveAddItemCartItems) and have
+				// no per-bundle multiplier to scale proportionally, so a
+				// partial quantity change on one child would silently leave
+				// its siblings out of sync. Reject it rather than guess.
+				return ErrBundleItemQuantityImmutable
 			}
+			c.Items[i].Quantity = quantity
+			c.Items[i].Subtotal = float64(quantity) * item.Price
 			c.UpdateTotals()
 			c.UpdatedAt = time.Now().UTC()
 			return nil
@@ -180,23 +268,43 @@ func (c *Cart) UpdateItemQuantity(productID string, quantity int, maxQuantity in
 	return ErrItemNotFound
 }
 
-// RemoveItem removes an item from the cart
+// RemoveItem removes an item from the cart. If productID was added as part
+// of a bundle (see AddItemRequest.BundleConfiguration), every other item
+// sharing its BundleParentID is removed with it, so a bundle is removed as
+// one atomic unit rather than leaving orphaned siblings behind.
 func (c *Cart) RemoveItem(productID string) error {
 	// Check if cart has expired
 	if time.Now().UTC().After(c.ExpiresAt) {
 		return ErrCartExpired
 	}
 
-	for i, item := range c.Items {
+	var bundleParentID string
+	found := false
+	for _, item := range c.Items {
 		if item.ProductID == productID {
-			c.Items = append(c.Items[:i], c.Items[i+1:]...)
-			c.UpdateTotals()
-			c.UpdatedAt = time.Now().UTC()
-			return nil
+			bundleParentID = item.BundleParentID
+			found = true
+			break
 		}
 	}
+	if !found {
+		return ErrItemNotFound
+	}
 
-	return ErrItemNotFound
+	remaining := make([]CartItem, 0, len(c.Items))
+	for _, item := range c.Items {
+		if item.ProductID == productID {
+			continue
+		}
+		if bundleParentID != "" && item.BundleParentID == bundleParentID {
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	c.Items = remaining
+	c.UpdateTotals()
+	c.UpdatedAt = time.Now().UTC()
+	return nil
 }
 
 // Clear removes all items from the cart
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEventType identifies a cart domain event recorded in the
+// transactional outbox for at-least-once delivery via Dapr pub/sub (see
+// internal/events).
+type OutboxEventType string
+
+const (
+	OutboxEventCartItemAdded   OutboxEventType = "cart.item_added"
+	OutboxEventCartItemUpdated OutboxEventType = "cart.item_updated"
+	OutboxEventCartItemRemoved OutboxEventType = "cart.item_removed"
+	OutboxEventCartCleared     OutboxEventType = "cart.cleared"
+	OutboxEventCartTransferred OutboxEventType = "cart.transferred"
+	// OutboxEventCartAbandoned is recorded by internal/purge.Sweeper for each
+	// cart it deletes for having expired, so analytics/marketing consumers
+	// can react to cart abandonment without polling the state store.
+	OutboxEventCartAbandoned OutboxEventType = "cart.abandoned"
+)
+
+// OutboxRecord is a cart domain event written alongside the cart state that
+// produced it, in the same Dapr state transaction (see
+// CartRepository.SaveCartWithOutbox), so a crash between saving the cart and
+// publishing the event can never lose it. internal/events.OutboxDispatcher
+// polls for records with Published false and publishes them to Dapr pub/sub.
+type OutboxRecord struct {
+	ID          string          `json:"id"`
+	Type        OutboxEventType `json:"type"`
+	UserID      string          `json:"userId"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Published   bool            `json:"published"`
+	PublishedAt *time.Time      `json:"publishedAt,omitempty"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// WebhookEventType identifies a cart lifecycle event delivered to subscribers.
+type WebhookEventType string
+
+const (
+	WebhookEventItemAdded   WebhookEventType = "cart.item_added"
+	WebhookEventItemRemoved WebhookEventType = "cart.item_removed"
+	WebhookEventItemUpdated WebhookEventType = "cart.item_updated"
+	WebhookEventCleared     WebhookEventType = "cart.cleared"
+	WebhookEventTransferred WebhookEventType = "cart.transferred"
+	WebhookEventAbandoned   WebhookEventType = "cart.abandoned"
+)
+
+// WebhookEvent is the payload POSTed to subscriber URLs.
+type WebhookEvent struct {
+	Type          WebhookEventType `json:"type"`
+	UserID        string           `json:"userId"`
+	CorrelationID string           `json:"correlationId,omitempty"`
+	Cart          *Cart            `json:"cart,omitempty"`
+	OccurredAt    time.Time        `json:"occurredAt"`
+}
+
+// WebhookSubscription is a tenant/user's registration for cart lifecycle events.
+type WebhookSubscription struct {
+	ID        string             `json:"id" redis:"id"`
+	OwnerID   string             `json:"ownerId" redis:"owner_id"`
+	URL       string             `json:"url" redis:"url"`
+	Secret    string             `json:"-" redis:"secret"`
+	Events    []WebhookEventType `json:"events" redis:"events"`
+	CreatedAt time.Time          `json:"createdAt" redis:"created_at"`
+}
+
+// Subscribes reports whether the subscription wants to hear about eventType.
+func (s *WebhookSubscription) Subscribes(eventType WebhookEventType) bool {
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookSubscriptionRequest is the body for registering a subscription.
+type CreateWebhookSubscriptionRequest struct {
+	URL    string             `json:"url" binding:"required,url"`
+	Secret string             `json:"secret" binding:"required"`
+	Events []WebhookEventType `json:"events" binding:"required,min=1"`
+}
+
+// UpdateWebhookSubscriptionRequest is the body for updating a subscription's
+// URL/secret/event filter.
+type UpdateWebhookSubscriptionRequest struct {
+	URL    string             `json:"url" binding:"required,url"`
+	Secret string             `json:"secret" binding:"required"`
+	Events []WebhookEventType `json:"events" binding:"required,min=1"`
+}
+
+// WebhookSubscriptionResponse is the response format for subscription CRUD.
+type WebhookSubscriptionResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Data    *WebhookSubscription `json:"data,omitempty"`
+}
+
+// WebhookSubscriptionListResponse is the response format for listing subscriptions.
+type WebhookSubscriptionListResponse struct {
+	Success bool                   `json:"success"`
+	Message string                 `json:"message"`
+	Data    []*WebhookSubscription `json:"data"`
+}
+
+// ErrWebhookSubscriptionNotFound is returned when a subscription ID doesn't
+// exist for the requesting owner.
+var ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
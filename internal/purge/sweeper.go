@@ -0,0 +1,186 @@
+// Package purge removes lapsed carts from the Dapr state store.
+//
+// Cart.IsExpired() is checked lazily on read (see
+// repository.DaprCartRepository.GetCart), but nothing actively deletes an
+// expired cart's key until something happens to read it, and Dapr state TTL
+// support varies by component — so expired carts can otherwise accumulate
+// indefinitely. Sweeper scans for them via CartRepository.FindExpiredCartUserIDs,
+// which pushes the expiry filter down to the state store's Query API where
+// that's supported (see DaprCartRepository.FindExpiredCartUserIDs); on
+// components that don't support querying, GetAllCartKeys/ListAllCartKeys
+// walking the maintained secondary cart-key index is the fallback. Sweeper
+// deletes expired carts in batches, on its own interval or on demand from
+// the admin purge endpoint (see internal/handlers.AdminHandler). Each
+// deleted cart also gets a best-effort cart.abandoned outbox record (see
+// internal/events) for analytics/marketing consumers; it carries no cart
+// content since the cart is already gone by the time it's written.
+package purge
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/events"
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/repository"
+	"github.com/aioutlet/cart-service/pkg/lock"
+	"go.uber.org/zap"
+)
+
+// sweeperResource is the pkg/lock.Locker "userID" leader election is held
+// under, so only one cart-service replica sweeps at a time. pkg/lock prefixes
+// it into a "cart:purge-sweeper" resource ID, which no real cart's userID
+// will collide with.
+const sweeperResource = "purge-sweeper"
+
+// sweeperOwnerID identifies this process as the lock holder; it doesn't need
+// to be unique beyond "not a real cart mutation", since only one sweep ever
+// runs per process.
+const sweeperOwnerID = "cart-purge-sweeper"
+
+// Result reports the outcome of a single sweep.
+type Result struct {
+	Scanned int
+	Deleted int
+}
+
+// Metrics is a point-in-time snapshot of the sweeper's cumulative activity,
+// exposed by Sweeper.Metrics for the /metrics endpoint.
+type Metrics struct {
+	TotalScanned int       `json:"totalScanned"`
+	TotalDeleted int       `json:"totalDeleted"`
+	LastRunAt    time.Time `json:"lastRunAt"`
+}
+
+// Sweeper periodically scans the cart repository for expired carts and
+// deletes them in batches, coordinating across replicas with a distributed
+// lock so only one sweep runs at a time.
+type Sweeper struct {
+	repo       repository.CartRepository
+	locker     lock.Locker
+	interval   time.Duration
+	batchSize  int
+	maxBatches int
+	logger     *zap.Logger
+
+	mu      sync.Mutex
+	metrics Metrics
+}
+
+// NewSweeper creates a Sweeper. batchSize bounds how many expired carts are
+// requested from the repository per page; a sweep keeps paging (up to a
+// safety cap of 1000 batches) until a page comes back short of batchSize.
+func NewSweeper(repo repository.CartRepository, locker lock.Locker, interval time.Duration, batchSize int, logger *zap.Logger) *Sweeper {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &Sweeper{
+		repo:       repo,
+		locker:     locker,
+		interval:   interval,
+		batchSize:  batchSize,
+		maxBatches: 1000,
+		logger:     logger,
+	}
+}
+
+// Run blocks, sweeping on Sweeper's interval until ctx is done.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Sweep(ctx); err != nil {
+				s.logger.Error("Purge sweep failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Sweep runs a single purge pass immediately, acquiring the leader-election
+// lock first so concurrent replicas (or an admin-triggered purge racing the
+// background sweeper) don't scan and delete the same carts twice. If the
+// lock is held elsewhere, Sweep returns a zero Result rather than blocking.
+func (s *Sweeper) Sweep(ctx context.Context) (Result, error) {
+	heldLock, err := s.locker.Acquire(ctx, sweeperResource, sweeperOwnerID)
+	if err != nil {
+		if err == lock.ErrNotAcquired {
+			s.logger.Debug("Skipping purge sweep, another replica is sweeping")
+			return Result{}, nil
+		}
+		return Result{}, err
+	}
+	defer s.locker.Release(ctx, heldLock)
+
+	now := time.Now().UTC()
+	var result Result
+
+	for batch := 0; batch < s.maxBatches; batch++ {
+		userIDs, err := s.repo.FindExpiredCartUserIDs(ctx, now, s.batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(userIDs) == 0 {
+			break
+		}
+
+		var abandoned []models.OutboxRecord
+		for _, userID := range userIDs {
+			result.Scanned++
+			if err := s.repo.DeleteCart(ctx, userID); err != nil {
+				s.logger.Warn("Failed to delete expired cart", zap.String("userID", userID), zap.Error(err))
+				continue
+			}
+			result.Deleted++
+
+			record, err := events.NewRecord(models.OutboxEventCartAbandoned, userID, nil)
+			if err != nil {
+				s.logger.Warn("Failed to build cart.abandoned outbox record", zap.String("userID", userID), zap.Error(err))
+				continue
+			}
+			abandoned = append(abandoned, record)
+		}
+
+		if len(abandoned) > 0 {
+			if err := s.repo.EnqueueOutboxRecords(ctx, abandoned); err != nil {
+				s.logger.Warn("Failed to enqueue cart.abandoned outbox records", zap.Error(err))
+			}
+		}
+
+		if len(userIDs) < s.batchSize {
+			break
+		}
+	}
+
+	s.recordMetrics(result, now)
+
+	if result.Scanned > 0 {
+		s.logger.Info("Purge sweep complete",
+			zap.Int("scanned", result.Scanned),
+			zap.Int("deleted", result.Deleted))
+	}
+
+	return result, nil
+}
+
+func (s *Sweeper) recordMetrics(result Result, runAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.metrics.TotalScanned += result.Scanned
+	s.metrics.TotalDeleted += result.Deleted
+	s.metrics.LastRunAt = runAt
+}
+
+// Metrics returns a snapshot of the sweeper's cumulative scanned/deleted
+// counts and last-run timestamp.
+func (s *Sweeper) Metrics() Metrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.metrics
+}
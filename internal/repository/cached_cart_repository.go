@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+)
+
+// ErrNoCacheEntry is returned internally by CachedCartRepository.get when
+// userID has no cached entry, so callers can tell an explicit cache miss
+// apart from ErrCacheIsInvalid.
+var ErrNoCacheEntry = errors.New("cart session cache: no entry for user")
+
+// ErrCacheIsInvalid is returned internally by CachedCartRepository.get when
+// userID's entry has expired, so the fallthrough to the wrapped repository
+// is explicit rather than implied by a bool.
+var ErrCacheIsInvalid = errors.New("cart session cache: entry is invalid")
+
+// cartCacheEntry is one cached cart, along with when it stops being served.
+type cartCacheEntry struct {
+	cart      *models.Cart
+	expiresAt time.Time
+}
+
+// CachedCartRepository wraps a CartRepository with a short-lived,
+// per-userID read-through cache in front of GetCart, modeled after Flamingo
+// commerce's session-scoped CartSessionCache: a single request's
+// GetCart/ValidateCart/GetCartSummary sequence (see
+// cartService.GetCartSummary) hits Redis at most once instead of once per
+// call. It only caches GetCart — GetCartWithETag is left to the wrapped
+// repository untouched, since a cached ETag could go stale the moment any
+// other writer saves and defeat the optimistic-concurrency check it exists
+// for.
+//
+// Every write path invalidates its entry immediately rather than waiting
+// out the TTL, so TTL only bounds how stale a read can be if the cart
+// changed through a path this type doesn't see (there shouldn't be one, but
+// the TTL is cheap insurance against ever serving a permanently stale
+// entry).
+type CachedCartRepository struct {
+	CartRepository
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cartCacheEntry
+}
+
+// NewCachedCartRepository wraps inner with a read-through cache whose
+// entries live for at most ttl. A non-positive ttl disables the cache:
+// every GetCart passes straight through to inner.
+func NewCachedCartRepository(inner CartRepository, ttl time.Duration) *CachedCartRepository {
+	return &CachedCartRepository{
+		CartRepository: inner,
+		ttl:            ttl,
+		entries:        make(map[string]cartCacheEntry),
+	}
+}
+
+// GetCart returns userID's cached cart if present and unexpired, otherwise
+// fetches it from the wrapped repository and caches the result.
+func (c *CachedCartRepository) GetCart(ctx context.Context, userID string) (*models.Cart, error) {
+	if cart, err := c.get(userID); err == nil {
+		return cart, nil
+	}
+
+	cart, err := c.CartRepository.GetCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.put(userID, cart)
+	return cart, nil
+}
+
+// SaveCart invalidates cart.UserID's cache entry and saves via the wrapped
+// repository.
+func (c *CachedCartRepository) SaveCart(ctx context.Context, cart *models.Cart) error {
+	c.Invalidate(cart.UserID)
+	return c.CartRepository.SaveCart(ctx, cart)
+}
+
+// SaveCartWithETag invalidates cart.UserID's cache entry and saves via the
+// wrapped repository.
+func (c *CachedCartRepository) SaveCartWithETag(ctx context.Context, cart *models.Cart, etag string) error {
+	c.Invalidate(cart.UserID)
+	return c.CartRepository.SaveCartWithETag(ctx, cart, etag)
+}
+
+// SaveCartWithToken invalidates cart.UserID's cache entry and saves via the
+// wrapped repository.
+func (c *CachedCartRepository) SaveCartWithToken(ctx context.Context, cart *models.Cart, token int64) error {
+	c.Invalidate(cart.UserID)
+	return c.CartRepository.SaveCartWithToken(ctx, cart, token)
+}
+
+// SaveCartWithOutbox invalidates cart.UserID's cache entry and saves via the
+// wrapped repository.
+func (c *CachedCartRepository) SaveCartWithOutbox(ctx context.Context, cart *models.Cart, records []models.OutboxRecord) error {
+	c.Invalidate(cart.UserID)
+	return c.CartRepository.SaveCartWithOutbox(ctx, cart, records)
+}
+
+// DeleteCart invalidates userID's cache entry and deletes via the wrapped
+// repository.
+func (c *CachedCartRepository) DeleteCart(ctx context.Context, userID string) error {
+	c.Invalidate(userID)
+	return c.CartRepository.DeleteCart(ctx, userID)
+}
+
+// AcquireLockWithToken invalidates userID's cache entry before acquiring the
+// lock, since a read-modify-write cycle is about to start and any cached
+// cart could be stale by the time it finishes.
+func (c *CachedCartRepository) AcquireLockWithToken(ctx context.Context, userID string, ttl time.Duration) (int64, bool, error) {
+	c.Invalidate(userID)
+	return c.CartRepository.AcquireLockWithToken(ctx, userID, ttl)
+}
+
+// Invalidate drops userID's cache entry, if any. Exposed for callers outside
+// this package that mutate a cart through a path CachedCartRepository
+// doesn't see (e.g. an admin tool writing directly to Redis).
+func (c *CachedCartRepository) Invalidate(userID string) {
+	c.mu.Lock()
+	delete(c.entries, userID)
+	c.mu.Unlock()
+}
+
+// get returns a cached, unexpired cart for userID, or ErrNoCacheEntry /
+// ErrCacheIsInvalid if the cache should be bypassed.
+func (c *CachedCartRepository) get(userID string) (*models.Cart, error) {
+	if c.ttl <= 0 {
+		return nil, ErrNoCacheEntry
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+	if !ok {
+		return nil, ErrNoCacheEntry
+	}
+	if time.Now().After(entry.expiresAt) {
+		c.Invalidate(userID)
+		return nil, ErrCacheIsInvalid
+	}
+	return cloneCart(entry.cart), nil
+}
+
+// put caches cart under userID for ttl.
+func (c *CachedCartRepository) put(userID string, cart *models.Cart) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.entries[userID] = cartCacheEntry{cart: cloneCart(cart), expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+// cloneCart deep-copies cart's Items slice so a cached entry and whatever a
+// caller does with a returned *models.Cart (e.g. cartService mutating it
+// in place before SaveCart) never alias the same backing array.
+func cloneCart(cart *models.Cart) *models.Cart {
+	clone := *cart
+	clone.Items = append([]models.CartItem(nil), cart.Items...)
+	return &clone
+}
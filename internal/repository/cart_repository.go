@@ -4,29 +4,167 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/crc32"
+	"strings"
 	"time"
 
 	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/pkg/tracing"
 	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 const (
-	cartKeyPrefix = "cart:"
-	cartLockPrefix = "cart_lock:"
+	cartKeyPrefix     = "cart:"
+	cartLockPrefix    = "cart_lock:"
+	cartLockSeqPrefix = "cart_lock_seq:"
+
+	// defaultCartIndexShardCount is the shard count ListCartKeys uses to
+	// bucket userIDs when a caller (or DaprCartRepository's config) doesn't
+	// specify one. It only needs to be consistent across calls to the same
+	// repository instance, not globally fixed, but sharing one default here
+	// keeps cartRepository and DaprCartRepository's shard numbering
+	// comparable.
+	defaultCartIndexShardCount = 16
+
+	// outboxPendingKey is the Redis hash of not-yet-published
+	// models.OutboxRecord entries, keyed by record ID. A hash (rather than a
+	// key per record) lets SaveCartWithOutbox add to it in the same
+	// transaction as the cart write without a separate key-listing step.
+	outboxPendingKey = "cart_outbox:pending"
 )
 
+// releaseLockWithTokenScript atomically deletes the lock key only if its
+// current value still equals the caller's token, so a holder whose lease
+// already expired can't delete a lock a newer holder has since acquired.
+var releaseLockWithTokenScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+else
+	return 0
+end
+`)
+
+// refreshLockWithTokenScript atomically extends the lock key's TTL only if
+// its current value still equals the caller's token, so a holder whose
+// fencing token has since been superseded can't keep a newer holder's lock
+// alive.
+var refreshLockWithTokenScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+else
+	return 0
+end
+`)
+
+// saveCartWithTokenScript atomically checks the lock key's current value
+// against the caller's token and, only if it still matches, SETs the cart
+// key in the same round trip. Folding the check and the save into one Lua
+// script closes the race a separate GET-then-SET would leave open: holder A
+// reads the token as still matching, holder B (whose AcquireLockWithToken
+// ran after A's lease expired) acquires a new token and saves, and A's
+// stale SET then lands anyway and clobbers B's write.
+var saveCartWithTokenScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[2], ARGV[2], "PX", ARGV[3])
+	return 1
+else
+	return 0
+end
+`)
+
+// deleteCartWithTokenScript is saveCartWithTokenScript's analogue for
+// DeleteCartWithToken: atomically checks the lock key's current value
+// against the caller's token and, only if it still matches, DELs the cart
+// key in the same round trip, for the same reason saveCartWithTokenScript
+// folds its check and SET together.
+var deleteCartWithTokenScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("DEL", KEYS[2])
+	return 1
+else
+	return 0
+end
+`)
+
 // CartRepository interface defines cart repository operations
 type CartRepository interface {
 	GetCart(ctx context.Context, userID string) (*models.Cart, error)
 	SaveCart(ctx context.Context, cart *models.Cart) error
+	// GetCartWithETag retrieves a cart along with an opaque ETag representing
+	// its current stored content, for optimistic-concurrency saves via
+	// SaveCartWithETag.
+	GetCartWithETag(ctx context.Context, userID string) (*models.Cart, string, error)
+	// SaveCartWithETag saves cart only if it is still at etag, returning
+	// models.ErrCartConflict if another writer has saved since the matching
+	// GetCartWithETag call.
+	SaveCartWithETag(ctx context.Context, cart *models.Cart, etag string) error
 	DeleteCart(ctx context.Context, userID string) error
 	SetCartTTL(ctx context.Context, userID string, ttl time.Duration) error
 	CartExists(ctx context.Context, userID string) (bool, error)
-	AcquireLock(ctx context.Context, userID string, ttl time.Duration) (bool, error)
-	ReleaseLock(ctx context.Context, userID string) error
+	// AcquireLockWithToken acquires a per-user lock, returning a
+	// monotonically increasing fencing token identifying this holder.
+	// Callers pass it to SaveCartWithToken and
+	// ReleaseLockWithToken so a holder whose lease already expired can't
+	// clobber a write made by whoever acquired the lock next.
+	AcquireLockWithToken(ctx context.Context, userID string, ttl time.Duration) (token int64, ok bool, err error)
+	// ReleaseLockWithToken releases the lock only if it is still held with
+	// token, via an atomic compare-and-delete, so a stale holder can't
+	// release a lock a newer holder has since acquired.
+	ReleaseLockWithToken(ctx context.Context, userID string, token int64) error
+	// SaveCartWithToken saves cart only if token still matches the lock's
+	// current holder, returning models.ErrLockTokenMismatch if another
+	// holder has acquired the lock since the matching AcquireLockWithToken
+	// call (e.g. this holder's lease expired mid-request).
+	SaveCartWithToken(ctx context.Context, cart *models.Cart, token int64) error
+	// DeleteCartWithToken deletes cart only if token still matches the
+	// lock's current holder, returning models.ErrLockTokenMismatch if
+	// another holder has acquired the lock since the matching
+	// AcquireLockWithToken call, the same guard SaveCartWithToken gives
+	// writes.
+	DeleteCartWithToken(ctx context.Context, userID string, token int64) error
+	// RefreshLock extends a held lock's TTL, for holders whose
+	// read-modify-write cycle runs long enough to risk the original lease
+	// expiring (e.g. a slow downstream call mid-AddItem). It's a no-op
+	// returning models.ErrLockTokenMismatch if token no longer matches the
+	// lock's current holder.
+	RefreshLock(ctx context.Context, userID string, token int64, ttl time.Duration) error
+	// GetAllCartKeys returns every userID with a stored cart, regardless of
+	// expiry. On DaprCartRepository this is backed by a maintained secondary
+	// index (see ListAllCartKeys) rather than a direct key scan, since most
+	// Dapr state-store components don't support listing keys by prefix.
 	GetAllCartKeys(ctx context.Context) ([]string, error)
+	// ListCartKeys returns the userIDs recorded in the given shard of the
+	// secondary cart-key index (see ListAllCartKeys). Shards are numbered
+	// [0, shardCount).
+	ListCartKeys(ctx context.Context, shard int) ([]string, error)
+	// ListAllCartKeys returns every userID across all index shards; it's
+	// what GetAllCartKeys delegates to on DaprCartRepository.
+	ListAllCartKeys(ctx context.Context) ([]string, error)
 	GetCartTTL(ctx context.Context, userID string) (time.Duration, error)
+	// FindExpiredCartUserIDs returns up to limit user IDs whose stored cart
+	// has already expired (ExpiresAt before cutoff), for the admin purge
+	// sweep in internal/purge.
+	FindExpiredCartUserIDs(ctx context.Context, cutoff time.Time, limit int) ([]string, error)
+	// SaveCartWithOutbox saves cart and writes records under a single state
+	// transaction, so a crash between persisting the cart and publishing its
+	// domain events can't lose them: internal/events.OutboxDispatcher polls
+	// ListPendingOutboxRecords and publishes whatever it finds.
+	SaveCartWithOutbox(ctx context.Context, cart *models.Cart, records []models.OutboxRecord) error
+	// EnqueueOutboxRecords writes records for publishing without touching any
+	// cart state, for callers that have no cart content to save alongside
+	// them (e.g. internal/purge.Sweeper recording a cart.abandoned event for
+	// a cart it has already deleted).
+	EnqueueOutboxRecords(ctx context.Context, records []models.OutboxRecord) error
+	// ListPendingOutboxRecords returns up to limit outbox records that
+	// haven't been published yet, for internal/events.OutboxDispatcher.
+	ListPendingOutboxRecords(ctx context.Context, limit int) ([]models.OutboxRecord, error)
+	// MarkOutboxRecordPublished records that recordID's event has been
+	// successfully published, so it isn't redelivered on the next poll.
+	MarkOutboxRecordPublished(ctx context.Context, recordID string) error
 }
 
 // cartRepository implements CartRepository interface
@@ -43,10 +181,36 @@ func NewCartRepository(client *redis.Client, logger *zap.Logger) CartRepository
 	}
 }
 
+// startRedisSpan starts a client-kind span for a Redis call, tagged with the
+// attributes a db-client instrumentation is expected to carry (db.system,
+// db.operation) plus this repository's own cart.key, mirroring how
+// pkg/tracing.TracedClient instruments Dapr calls a layer up.
+func startRedisSpan(ctx context.Context, op, key string) (context.Context, trace.Span) {
+	ctx, span := tracing.GetTracer().Start(ctx, "redis."+op, trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attribute.String("db.system", "redis"),
+		attribute.String("db.operation", op),
+		attribute.String("cart.key", key),
+	)
+	return ctx, span
+}
+
+// endRedisSpan records err on span, if non-nil, and ends it. Pair with
+// startRedisSpan via defer.
+func endRedisSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 // GetCart retrieves a cart from Redis
-func (r *cartRepository) GetCart(ctx context.Context, userID string) (*models.Cart, error) {
+func (r *cartRepository) GetCart(ctx context.Context, userID string) (_ *models.Cart, err error) {
 	key := r.getCartKey(userID)
-	
+	ctx, span := startRedisSpan(ctx, "get_cart", key)
+	defer func() { endRedisSpan(span, err) }()
+
 	data, err := r.client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
@@ -81,9 +245,11 @@ func (r *cartRepository) GetCart(ctx context.Context, userID string) (*models.Ca
 }
 
 // SaveCart saves a cart to Redis
-func (r *cartRepository) SaveCart(ctx context.Context, cart *models.Cart) error {
+func (r *cartRepository) SaveCart(ctx context.Context, cart *models.Cart) (err error) {
 	key := r.getCartKey(cart.UserID)
-	
+	ctx, span := startRedisSpan(ctx, "save_cart", key)
+	defer func() { endRedisSpan(span, err) }()
+
 	data, err := json.Marshal(cart)
 	if err != nil {
 		r.logger.Error("Failed to marshal cart data", 
@@ -113,11 +279,76 @@ func (r *cartRepository) SaveCart(ctx context.Context, cart *models.Cart) error
 	return nil
 }
 
+// GetCartWithETag retrieves a cart along with a content-hash ETag. Redis has
+// no native ETag concept; this is a best-effort shim kept for
+// CartRepository interface compatibility (this repository predates
+// pkg/lock and isn't wired into any cmd/* entrypoint, which uses
+// DaprCartRepository's real ETag support instead).
+func (r *cartRepository) GetCartWithETag(ctx context.Context, userID string) (_ *models.Cart, _ string, err error) {
+	key := r.getCartKey(userID)
+	ctx, span := startRedisSpan(ctx, "get_cart_with_etag", key)
+	defer func() { endRedisSpan(span, err) }()
+
+	data, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, "", models.ErrCartNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	var cart models.Cart
+	if err := json.Unmarshal([]byte(data), &cart); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal cart: %w", err)
+	}
+
+	if cart.IsExpired() {
+		_ = r.DeleteCart(ctx, userID)
+		return nil, "", models.ErrCartExpired
+	}
+
+	return &cart, contentETag([]byte(data)), nil
+}
+
+// SaveCartWithETag saves cart only if the stored value still hashes to etag.
+// This is a plain GET-then-SET check rather than a true compare-and-swap, so
+// it's not race-free under Redis alone; see GetCartWithETag.
+func (r *cartRepository) SaveCartWithETag(ctx context.Context, cart *models.Cart, etag string) (err error) {
+	key := r.getCartKey(cart.UserID)
+	ctx, span := startRedisSpan(ctx, "save_cart_with_etag", key)
+	defer func() { endRedisSpan(span, err) }()
+
+	current, err := r.client.Get(ctx, key).Result()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to read current cart: %w", err)
+	}
+	if err != redis.Nil && contentETag([]byte(current)) != etag {
+		return models.ErrCartConflict
+	}
+
+	return r.SaveCart(ctx, cart)
+}
+
+func contentETag(data []byte) string {
+	return fmt.Sprintf("%x", crc32.ChecksumIEEE(data))
+}
+
+// shardForUserID deterministically maps userID to one of shardCount shards
+// via CRC32, so every repository instance (and every replica) computes the
+// same shard for a given cart without coordination. Used to bucket
+// DaprCartRepository's secondary cart-key index, and mirrored here so
+// cartRepository's ListCartKeys numbers shards the same way.
+func shardForUserID(userID string, shardCount int) int {
+	return int(crc32.ChecksumIEEE([]byte(userID))) % shardCount
+}
+
 // DeleteCart deletes a cart from Redis
-func (r *cartRepository) DeleteCart(ctx context.Context, userID string) error {
+func (r *cartRepository) DeleteCart(ctx context.Context, userID string) (err error) {
 	key := r.getCartKey(userID)
-	
-	err := r.client.Del(ctx, key).Err()
+	ctx, span := startRedisSpan(ctx, "delete_cart", key)
+	defer func() { endRedisSpan(span, err) }()
+
+	err = r.client.Del(ctx, key).Err()
 	if err != nil {
 		r.logger.Error("Failed to delete cart from Redis", 
 			zap.String("userID", userID), 
@@ -130,10 +361,12 @@ func (r *cartRepository) DeleteCart(ctx context.Context, userID string) error {
 }
 
 // SetCartTTL sets the TTL for a cart
-func (r *cartRepository) SetCartTTL(ctx context.Context, userID string, ttl time.Duration) error {
+func (r *cartRepository) SetCartTTL(ctx context.Context, userID string, ttl time.Duration) (err error) {
 	key := r.getCartKey(userID)
-	
-	err := r.client.Expire(ctx, key, ttl).Err()
+	ctx, span := startRedisSpan(ctx, "set_cart_ttl", key)
+	defer func() { endRedisSpan(span, err) }()
+
+	err = r.client.Expire(ctx, key, ttl).Err()
 	if err != nil {
 		r.logger.Error("Failed to set cart TTL", 
 			zap.String("userID", userID),
@@ -146,9 +379,11 @@ func (r *cartRepository) SetCartTTL(ctx context.Context, userID string, ttl time
 }
 
 // CartExists checks if a cart exists in Redis
-func (r *cartRepository) CartExists(ctx context.Context, userID string) (bool, error) {
+func (r *cartRepository) CartExists(ctx context.Context, userID string) (_ bool, err error) {
 	key := r.getCartKey(userID)
-	
+	ctx, span := startRedisSpan(ctx, "cart_exists", key)
+	defer func() { endRedisSpan(span, err) }()
+
 	exists, err := r.client.Exists(ctx, key).Result()
 	if err != nil {
 		r.logger.Error("Failed to check cart existence", 
@@ -160,59 +395,193 @@ func (r *cartRepository) CartExists(ctx context.Context, userID string) (bool, e
 	return exists > 0, nil
 }
 
-// AcquireLock acquires a distributed lock for cart operations
-func (r *cartRepository) AcquireLock(ctx context.Context, userID string, ttl time.Duration) (bool, error) {
-	lockKey := r.getLockKey(userID)
-	
-	// Use SET with NX (only if not exists) and EX (expiry) options
-	result, err := r.client.SetNX(ctx, lockKey, "locked", ttl).Result()
+// AcquireLockWithToken acquires a per-user lock, returning
+// a monotonically increasing fencing token (backed by a Redis INCR counter)
+// identifying this holder.
+func (r *cartRepository) AcquireLockWithToken(ctx context.Context, userID string, ttl time.Duration) (int64, bool, error) {
+	token, err := r.client.Incr(ctx, r.getLockSeqKey(userID)).Result()
 	if err != nil {
-		r.logger.Error("Failed to acquire cart lock", 
-			zap.String("userID", userID), 
+		r.logger.Error("Failed to generate cart lock token",
+			zap.String("userID", userID),
 			zap.Error(err))
-		return false, fmt.Errorf("failed to acquire lock: %w", err)
+		return 0, false, fmt.Errorf("failed to generate lock token: %w", err)
 	}
 
-	if result {
-		r.logger.Debug("Cart lock acquired", zap.String("userID", userID))
+	ok, err := r.client.SetNX(ctx, r.getLockKey(userID), token, ttl).Result()
+	if err != nil {
+		r.logger.Error("Failed to acquire cart lock",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return 0, false, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !ok {
+		return 0, false, nil
 	}
 
-	return result, nil
+	r.logger.Debug("Cart lock acquired with fencing token",
+		zap.String("userID", userID), zap.Int64("token", token))
+	return token, true, nil
 }
 
-// ReleaseLock releases a distributed lock
-func (r *cartRepository) ReleaseLock(ctx context.Context, userID string) error {
+// ReleaseLockWithToken releases the lock only if its current value still
+// equals token, via an atomic Lua compare-and-delete.
+func (r *cartRepository) ReleaseLockWithToken(ctx context.Context, userID string, token int64) error {
 	lockKey := r.getLockKey(userID)
-	
-	err := r.client.Del(ctx, lockKey).Err()
+
+	deleted, err := releaseLockWithTokenScript.Run(ctx, r.client, []string{lockKey}, token).Int64()
 	if err != nil {
-		r.logger.Error("Failed to release cart lock", 
-			zap.String("userID", userID), 
+		r.logger.Error("Failed to release cart lock",
+			zap.String("userID", userID),
 			zap.Error(err))
 		return fmt.Errorf("failed to release lock: %w", err)
 	}
 
-	r.logger.Debug("Cart lock released", zap.String("userID", userID))
+	if deleted == 0 {
+		r.logger.Warn("Cart lock release skipped: token no longer matches current holder",
+			zap.String("userID", userID), zap.Int64("token", token))
+		return nil
+	}
+
+	r.logger.Debug("Cart lock released", zap.String("userID", userID), zap.Int64("token", token))
+	return nil
+}
+
+// RefreshLock extends the lock key's TTL only if its current value still
+// equals token, via the same compare-and-swap approach as
+// ReleaseLockWithToken, so a stale holder can't keep a newer holder's lock
+// alive by refreshing it out from under them.
+func (r *cartRepository) RefreshLock(ctx context.Context, userID string, token int64, ttl time.Duration) error {
+	lockKey := r.getLockKey(userID)
+
+	refreshed, err := refreshLockWithTokenScript.Run(ctx, r.client, []string{lockKey}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		r.logger.Error("Failed to refresh cart lock",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return fmt.Errorf("failed to refresh lock: %w", err)
+	}
+
+	if refreshed == 0 {
+		return models.ErrLockTokenMismatch
+	}
+
+	r.logger.Debug("Cart lock refreshed", zap.String("userID", userID), zap.Int64("token", token))
+	return nil
+}
+
+// SaveCartWithToken saves cart only if token still matches the lock's
+// current holder, atomically via saveCartWithTokenScript so a holder whose
+// lease already expired can't clobber a write made by whoever acquired the
+// lock next (see saveCartWithTokenScript for why a separate check-then-SET
+// isn't safe here).
+func (r *cartRepository) SaveCartWithToken(ctx context.Context, cart *models.Cart, token int64) error {
+	data, err := json.Marshal(cart)
+	if err != nil {
+		r.logger.Error("Failed to marshal cart data",
+			zap.String("userID", cart.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to marshal cart: %w", err)
+	}
+
+	// Calculate TTL based on cart expiry, same as SaveCart.
+	ttl := time.Until(cart.ExpiresAt)
+	if ttl <= 0 {
+		ttl = time.Minute // Minimum TTL of 1 minute
+	}
+
+	saved, err := saveCartWithTokenScript.Run(ctx, r.client,
+		[]string{r.getLockKey(cart.UserID), r.getCartKey(cart.UserID)},
+		token, data, ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		r.logger.Error("Failed to save cart with lock token",
+			zap.String("userID", cart.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save cart: %w", err)
+	}
+	if saved == 0 {
+		return models.ErrLockTokenMismatch
+	}
+
+	r.logger.Debug("Cart saved successfully with fencing token",
+		zap.String("userID", cart.UserID), zap.Duration("ttl", ttl))
+	return nil
+}
+
+// DeleteCartWithToken deletes cart only if token still matches the lock's
+// current holder, atomically via deleteCartWithTokenScript, the same
+// fencing guard SaveCartWithToken gives writes.
+func (r *cartRepository) DeleteCartWithToken(ctx context.Context, userID string, token int64) error {
+	deleted, err := deleteCartWithTokenScript.Run(ctx, r.client,
+		[]string{r.getLockKey(userID), r.getCartKey(userID)},
+		token,
+	).Int64()
+	if err != nil {
+		r.logger.Error("Failed to delete cart with lock token",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	if deleted == 0 {
+		return models.ErrLockTokenMismatch
+	}
+
+	r.logger.Debug("Cart deleted successfully with fencing token", zap.String("userID", userID))
 	return nil
 }
 
-// GetAllCartKeys retrieves all cart keys for cleanup operations
+// GetAllCartKeys retrieves every userID with a stored cart via a Redis KEYS
+// scan. Unlike DaprCartRepository, Redis supports listing keys by prefix
+// directly, so this repository has no need for the sharded secondary index
+// DaprCartRepository maintains; ListCartKeys/ListAllCartKeys below just
+// bucket this same scan by shardForUserID for interface compatibility.
 func (r *cartRepository) GetAllCartKeys(ctx context.Context) ([]string, error) {
 	pattern := cartKeyPrefix + "*"
-	
+
 	keys, err := r.client.Keys(ctx, pattern).Result()
 	if err != nil {
 		r.logger.Error("Failed to get cart keys", zap.Error(err))
 		return nil, fmt.Errorf("failed to get cart keys: %w", err)
 	}
 
-	return keys, nil
+	userIDs := make([]string, len(keys))
+	for i, key := range keys {
+		userIDs[i] = strings.TrimPrefix(key, cartKeyPrefix)
+	}
+
+	return userIDs, nil
+}
+
+// ListCartKeys returns the userIDs from GetAllCartKeys that hash to shard,
+// using the same shardForUserID function DaprCartRepository indexes by.
+func (r *cartRepository) ListCartKeys(ctx context.Context, shard int) ([]string, error) {
+	all, err := r.GetAllCartKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(all))
+	for _, userID := range all {
+		if shardForUserID(userID, defaultCartIndexShardCount) == shard {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+// ListAllCartKeys is equivalent to GetAllCartKeys here: Redis's KEYS scan
+// already lists every cart in one pass, so there's no per-shard index to
+// walk.
+func (r *cartRepository) ListAllCartKeys(ctx context.Context) ([]string, error) {
+	return r.GetAllCartKeys(ctx)
 }
 
 // GetCartTTL gets the remaining TTL for a cart
-func (r *cartRepository) GetCartTTL(ctx context.Context, userID string) (time.Duration, error) {
+func (r *cartRepository) GetCartTTL(ctx context.Context, userID string) (_ time.Duration, err error) {
 	key := r.getCartKey(userID)
-	
+	ctx, span := startRedisSpan(ctx, "get_cart_ttl", key)
+	defer func() { endRedisSpan(span, err) }()
+
 	ttl, err := r.client.TTL(ctx, key).Result()
 	if err != nil {
 		r.logger.Error("Failed to get cart TTL", 
@@ -224,6 +593,137 @@ func (r *cartRepository) GetCartTTL(ctx context.Context, userID string) (time.Du
 	return ttl, nil
 }
 
+// FindExpiredCartUserIDs scans all cart keys and returns up to limit user
+// IDs whose cart has already expired. Redis itself expires carts via the
+// TTL set in SaveCart, so this mainly catches carts saved with a stale
+// ExpiresAt before their TTL elapses; kept for CartRepository interface
+// compatibility with DaprCartRepository's query-based implementation.
+func (r *cartRepository) FindExpiredCartUserIDs(ctx context.Context, cutoff time.Time, limit int) ([]string, error) {
+	keys, err := r.GetAllCartKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, limit)
+	for _, key := range keys {
+		if len(userIDs) >= limit {
+			break
+		}
+
+		data, err := r.client.Get(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+
+		var cart models.Cart
+		if err := json.Unmarshal([]byte(data), &cart); err != nil {
+			continue
+		}
+
+		if cart.ExpiresAt.Before(cutoff) {
+			userIDs = append(userIDs, strings.TrimPrefix(key, cartKeyPrefix))
+		}
+	}
+
+	return userIDs, nil
+}
+
+// SaveCartWithOutbox saves cart and queues records in a single Redis
+// transaction (MULTI/EXEC via TxPipelined), so a process that dies right
+// after this call returns never leaves a published-looking cart with its
+// domain events lost.
+func (r *cartRepository) SaveCartWithOutbox(ctx context.Context, cart *models.Cart, records []models.OutboxRecord) error {
+	key := r.getCartKey(cart.UserID)
+
+	data, err := json.Marshal(cart)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cart: %w", err)
+	}
+
+	ttl := time.Until(cart.ExpiresAt)
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ttl)
+		for _, record := range records {
+			recordData, err := json.Marshal(record)
+			if err != nil {
+				return fmt.Errorf("failed to marshal outbox record %s: %w", record.ID, err)
+			}
+			pipe.HSet(ctx, outboxPendingKey, record.ID, recordData)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to save cart with outbox",
+			zap.String("userID", cart.UserID), zap.Error(err))
+		return fmt.Errorf("failed to save cart with outbox: %w", err)
+	}
+
+	r.logger.Debug("Cart saved with outbox records",
+		zap.String("userID", cart.UserID), zap.Int("recordCount", len(records)))
+	return nil
+}
+
+// EnqueueOutboxRecords writes records to outboxPendingKey without touching
+// any cart key.
+func (r *cartRepository) EnqueueOutboxRecords(ctx context.Context, records []models.OutboxRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(records))
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox record %s: %w", record.ID, err)
+		}
+		fields[record.ID] = data
+	}
+
+	if err := r.client.HSet(ctx, outboxPendingKey, fields).Err(); err != nil {
+		r.logger.Error("Failed to enqueue outbox records", zap.Error(err))
+		return fmt.Errorf("failed to enqueue outbox records: %w", err)
+	}
+	return nil
+}
+
+// ListPendingOutboxRecords returns up to limit entries from outboxPendingKey.
+func (r *cartRepository) ListPendingOutboxRecords(ctx context.Context, limit int) ([]models.OutboxRecord, error) {
+	entries, err := r.client.HGetAll(ctx, outboxPendingKey).Result()
+	if err != nil {
+		r.logger.Error("Failed to list pending outbox records", zap.Error(err))
+		return nil, fmt.Errorf("failed to list pending outbox records: %w", err)
+	}
+
+	records := make([]models.OutboxRecord, 0, len(entries))
+	for _, raw := range entries {
+		var record models.OutboxRecord
+		if err := json.Unmarshal([]byte(raw), &record); err != nil {
+			r.logger.Warn("Skipping unparseable outbox record", zap.Error(err))
+			continue
+		}
+		records = append(records, record)
+		if len(records) >= limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+// MarkOutboxRecordPublished removes recordID from outboxPendingKey so it
+// isn't redelivered; there's no need to retain published records in Redis.
+func (r *cartRepository) MarkOutboxRecordPublished(ctx context.Context, recordID string) error {
+	if err := r.client.HDel(ctx, outboxPendingKey, recordID).Err(); err != nil {
+		r.logger.Error("Failed to mark outbox record published",
+			zap.String("recordID", recordID), zap.Error(err))
+		return fmt.Errorf("failed to mark outbox record published: %w", err)
+	}
+	return nil
+}
+
 // Helper methods
 func (r *cartRepository) getCartKey(userID string) string {
 	return cartKeyPrefix + userID
@@ -232,3 +732,7 @@ func (r *cartRepository) getCartKey(userID string) string {
 func (r *cartRepository) getLockKey(userID string) string {
 	return cartLockPrefix + userID
 }
+
+func (r *cartRepository) getLockSeqKey(userID string) string {
+	return cartLockSeqPrefix + userID
+}
@@ -4,38 +4,89 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/pkg/tracing"
 	dapr "github.com/dapr/go-sdk/client"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	cartKeyPrefix = "cart:"
+
+	// lockTokenKeyPrefix stores the monotonically increasing fencing token
+	// each AcquireLockWithToken call issues, as a CAS counter guarded by the
+	// state store's own ETag.
+	lockTokenKeyPrefix = "lock_token:"
+
+	// maxLockTokenRetries bounds the CAS retry loop AcquireLockWithToken
+	// runs against lockTokenKeyPrefix when it loses a race with a
+	// concurrent holder.
+	maxLockTokenRetries = 5
+
+	// outboxKeyPrefix namespaces models.OutboxRecord state entries written
+	// by SaveCartWithOutbox, one key per record (Dapr state stores have no
+	// native collection type to hold them all under one key, unlike the
+	// Redis-backed cartRepository's hash).
+	outboxKeyPrefix = "outbox:"
+
+	// cartIndexKeyPrefix namespaces the sharded secondary index of cart
+	// userIDs that SaveCart/DeleteCart maintain, keyed by
+	// shardForUserID(userID, indexShardCount). Most Dapr state-store
+	// components don't support listing keys by prefix, so without this
+	// index GetAllCartKeys/ListAllCartKeys would have no way to enumerate
+	// carts; FindExpiredCartUserIDs's Query API pushdown remains the
+	// primary mechanism internal/purge.Sweeper uses where it's available,
+	// since it's cheaper than fetching every cart to check expiry, but this
+	// index (and the GetCart-based walk it enables) is what makes
+	// GetAllCartKeys actually work on components that don't support it.
+	cartIndexKeyPrefix = "cart-index:shard-"
 )
 
 // DaprCartRepository implements CartRepository using Dapr State Management
 type DaprCartRepository struct {
-	client         dapr.Client
-	stateStoreName string
-	logger         *zap.Logger
+	client          dapr.Client
+	stateStoreName  string
+	indexShardCount int
+	// slowOpThreshold is the tracing.StepTrace.LogIfLong threshold GetCart
+	// and SaveCart use to decide whether their per-step timing breakdown
+	// (marshal/unmarshal, Dapr call, expiry check) is worth logging.
+	slowOpThreshold time.Duration
+	logger          *zap.Logger
 }
 
-// NewDaprCartRepository creates a new Dapr-based cart repository
-func NewDaprCartRepository(client dapr.Client, stateStoreName string, logger *zap.Logger) CartRepository {
+// NewDaprCartRepository creates a new Dapr-based cart repository.
+// indexShardCount sets how many shards the secondary cart-key index (see
+// cartIndexKeyPrefix) is split across; a value <= 0 falls back to
+// defaultCartIndexShardCount. slowOpThreshold is passed to GetCart/SaveCart's
+// tracing.StepTrace calls.
+func NewDaprCartRepository(client dapr.Client, stateStoreName string, indexShardCount int, slowOpThreshold time.Duration, logger *zap.Logger) CartRepository {
+	if indexShardCount <= 0 {
+		indexShardCount = defaultCartIndexShardCount
+	}
 	return &DaprCartRepository{
-		client:         client,
-		stateStoreName: stateStoreName,
-		logger:         logger,
+		client:          client,
+		stateStoreName:  stateStoreName,
+		indexShardCount: indexShardCount,
+		slowOpThreshold: slowOpThreshold,
+		logger:          logger,
 	}
 }
 
 // GetCart retrieves a cart from Dapr state store
 func (r *DaprCartRepository) GetCart(ctx context.Context, userID string) (*models.Cart, error) {
+	step := tracing.NewStepTrace(r.logger, "cart.get_cart", userID)
+	defer step.LogIfLong(r.slowOpThreshold)
+
 	key := r.getCartKey(userID)
 
 	item, err := r.client.GetState(ctx, r.stateStoreName, key, nil)
+	step.Step("dapr call")
 	if err != nil {
 		r.logger.Error("Failed to get cart from Dapr state store",
 			zap.String("userID", userID),
@@ -54,6 +105,7 @@ func (r *DaprCartRepository) GetCart(ctx context.Context, userID string) (*model
 			zap.Error(err))
 		return nil, fmt.Errorf("failed to unmarshal cart: %w", err)
 	}
+	step.Step("unmarshal")
 
 	// Check if cart has expired
 	if cart.IsExpired() {
@@ -65,12 +117,18 @@ func (r *DaprCartRepository) GetCart(ctx context.Context, userID string) (*model
 		}
 		return nil, models.ErrCartExpired
 	}
+	step.Step("expiry check")
 
 	return &cart, nil
 }
 
-// SaveCart saves a cart to Dapr state store with ETag-based concurrency control
+// SaveCart saves a cart to Dapr state store, and, in the same state
+// transaction, adds its userID to the secondary cart-key index (see
+// cartIndexKeyPrefix) if it isn't already recorded there.
 func (r *DaprCartRepository) SaveCart(ctx context.Context, cart *models.Cart) error {
+	step := tracing.NewStepTrace(r.logger, "cart.save_cart", cart.UserID)
+	defer step.LogIfLong(r.slowOpThreshold)
+
 	key := r.getCartKey(cart.UserID)
 
 	data, err := json.Marshal(cart)
@@ -80,6 +138,7 @@ func (r *DaprCartRepository) SaveCart(ctx context.Context, cart *models.Cart) er
 			zap.Error(err))
 		return fmt.Errorf("failed to marshal cart: %w", err)
 	}
+	step.Step("marshal")
 
 	// Calculate TTL in seconds
 	ttl := int(time.Until(cart.ExpiresAt).Seconds())
@@ -92,8 +151,21 @@ func (r *DaprCartRepository) SaveCart(ctx context.Context, cart *models.Cart) er
 		"ttlInSeconds": fmt.Sprintf("%d", ttl),
 	}
 
-	// Save state with metadata
-	err = r.client.SaveState(ctx, r.stateStoreName, key, data, metadata)
+	ops := []*dapr.StateOperation{
+		{
+			Type: dapr.StateOperationTypeUpsert,
+			Item: &dapr.SetStateItem{Key: key, Value: data, Metadata: metadata},
+		},
+	}
+	if indexOp, err := r.indexOpForSave(ctx, cart.UserID); err != nil {
+		r.logger.Warn("Failed to prepare cart index update, saving cart without it",
+			zap.String("userID", cart.UserID), zap.Error(err))
+	} else if indexOp != nil {
+		ops = append(ops, indexOp)
+	}
+
+	err = r.client.ExecuteStateTransaction(ctx, r.stateStoreName, nil, ops)
+	step.Step("dapr call")
 	if err != nil {
 		r.logger.Error("Failed to save cart to Dapr state store",
 			zap.String("userID", cart.UserID),
@@ -108,12 +180,130 @@ func (r *DaprCartRepository) SaveCart(ctx context.Context, cart *models.Cart) er
 	return nil
 }
 
-// DeleteCart deletes a cart from Dapr state store
-func (r *DaprCartRepository) DeleteCart(ctx context.Context, userID string) error {
+// GetCartWithETag retrieves a cart along with the Dapr state ETag, for
+// optimistic-concurrency saves via SaveCartWithETag.
+func (r *DaprCartRepository) GetCartWithETag(ctx context.Context, userID string) (*models.Cart, string, error) {
 	key := r.getCartKey(userID)
 
-	err := r.client.DeleteState(ctx, r.stateStoreName, key, nil)
+	item, err := r.client.GetState(ctx, r.stateStoreName, key, nil)
+	if err != nil {
+		r.logger.Error("Failed to get cart from Dapr state store",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return nil, "", fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	if item.Value == nil || len(item.Value) == 0 {
+		return nil, "", models.ErrCartNotFound
+	}
+
+	var cart models.Cart
+	if err := json.Unmarshal(item.Value, &cart); err != nil {
+		r.logger.Error("Failed to unmarshal cart data",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return nil, "", fmt.Errorf("failed to unmarshal cart: %w", err)
+	}
+
+	if cart.IsExpired() {
+		r.logger.Info("Cart has expired, deleting", zap.String("userID", userID))
+		if err := r.DeleteCart(ctx, userID); err != nil {
+			r.logger.Error("Failed to delete expired cart",
+				zap.String("userID", userID),
+				zap.Error(err))
+		}
+		return nil, "", models.ErrCartExpired
+	}
+
+	return &cart, item.Etag, nil
+}
+
+// SaveCartWithETag saves a cart to the Dapr state store using first-write-wins
+// concurrency: if the stored ETag has moved on since the matching
+// GetCartWithETag call, the save is rejected with models.ErrCartConflict so
+// the caller can reload the cart, re-apply its mutation, and retry.
+func (r *DaprCartRepository) SaveCartWithETag(ctx context.Context, cart *models.Cart, etag string) error {
+	key := r.getCartKey(cart.UserID)
+
+	data, err := json.Marshal(cart)
 	if err != nil {
+		r.logger.Error("Failed to marshal cart data",
+			zap.String("userID", cart.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to marshal cart: %w", err)
+	}
+
+	ttl := int(time.Until(cart.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		ttl = 60
+	}
+	metadata := map[string]string{
+		"ttlInSeconds": fmt.Sprintf("%d", ttl),
+	}
+
+	if err := r.client.SaveStateWithETag(ctx, r.stateStoreName, key, data, etag, metadata); err != nil {
+		if isETagConflict(err) {
+			return models.ErrCartConflict
+		}
+		r.logger.Error("Failed to save cart to Dapr state store with ETag",
+			zap.String("userID", cart.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save cart: %w", err)
+	}
+
+	// The cart write above is already conditioned on its own ETag; the
+	// index update is a separate, best-effort call rather than folded into
+	// a transaction, since a transaction here would need to condition on
+	// the cart key's ETag too, and SaveCartWithETag's whole contract is
+	// "reject if etag doesn't match" — a partial transaction failure would
+	// need to surface as models.ErrCartConflict either way. Worst case a
+	// missed index update just means this userID is briefly absent from
+	// GetAllCartKeys until its next save.
+	if err := r.addToIndex(ctx, cart.UserID); err != nil {
+		r.logger.Warn("Failed to update cart index after ETag save",
+			zap.String("userID", cart.UserID), zap.Error(err))
+	}
+
+	r.logger.Debug("Cart saved successfully with ETag",
+		zap.String("userID", cart.UserID),
+		zap.Int("ttlSeconds", ttl))
+
+	return nil
+}
+
+// isETagConflict reports whether err indicates SaveStateWithETag was
+// rejected because the stored ETag no longer matches (another writer saved
+// the cart first), as opposed to a transient or unexpected failure.
+func isETagConflict(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Aborted, codes.FailedPrecondition:
+		return true
+	default:
+		return false
+	}
+}
+
+// DeleteCart deletes a cart from Dapr state store, and, in the same state
+// transaction, removes its userID from the secondary cart-key index if it's
+// recorded there.
+func (r *DaprCartRepository) DeleteCart(ctx context.Context, userID string) error {
+	key := r.getCartKey(userID)
+
+	ops := []*dapr.StateOperation{
+		{Type: dapr.StateOperationTypeDelete, Item: &dapr.SetStateItem{Key: key}},
+	}
+	if indexOp, err := r.indexOpForDelete(ctx, userID); err != nil {
+		r.logger.Warn("Failed to prepare cart index update, deleting cart without it",
+			zap.String("userID", userID), zap.Error(err))
+	} else if indexOp != nil {
+		ops = append(ops, indexOp)
+	}
+
+	if err := r.client.ExecuteStateTransaction(ctx, r.stateStoreName, nil, ops); err != nil {
 		r.logger.Error("Failed to delete cart from Dapr state store",
 			zap.String("userID", userID),
 			zap.Error(err))
@@ -155,33 +345,422 @@ func (r *DaprCartRepository) CartExists(ctx context.Context, userID string) (boo
 	return item.Value != nil && len(item.Value) > 0, nil
 }
 
-// AcquireLock - with Dapr State Management, ETag-based concurrency replaces distributed locks
-// This method is kept for interface compatibility but uses ETag mechanism
-func (r *DaprCartRepository) AcquireLock(ctx context.Context, userID string, ttl time.Duration) (bool, error) {
-	// With Dapr State Management, we don't need explicit locks
-	// ETag-based optimistic concurrency control handles this automatically
-	r.logger.Debug("Lock acquisition not needed with Dapr ETag-based concurrency",
-		zap.String("userID", userID))
-	return true, nil
+// AcquireLockWithToken issues a monotonically increasing fencing token via a
+// compare-and-swap loop against the state store's own ETag, and records it
+// as the lock's current holder. SaveCartWithToken uses the recorded token to
+// reject a write from a holder whose lease already expired and has since
+// been superseded.
+func (r *DaprCartRepository) AcquireLockWithToken(ctx context.Context, userID string, ttl time.Duration) (int64, bool, error) {
+	key := r.getLockTokenKey(userID)
+	metadata := map[string]string{"ttlInSeconds": fmt.Sprintf("%d", int(ttl.Seconds()))}
+
+	for attempt := 0; attempt < maxLockTokenRetries; attempt++ {
+		item, err := r.client.GetState(ctx, r.stateStoreName, key, nil)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to read cart lock token: %w", err)
+		}
+
+		var current int64
+		if len(item.Value) > 0 {
+			current, err = strconv.ParseInt(string(item.Value), 10, 64)
+			if err != nil {
+				return 0, false, fmt.Errorf("failed to parse cart lock token: %w", err)
+			}
+		}
+		next := current + 1
+
+		if err := r.client.SaveStateWithETag(ctx, r.stateStoreName, key, []byte(strconv.FormatInt(next, 10)), item.Etag, metadata); err != nil {
+			if isETagConflict(err) {
+				continue
+			}
+			return 0, false, fmt.Errorf("failed to save cart lock token: %w", err)
+		}
+
+		r.logger.Debug("Cart lock token issued",
+			zap.String("userID", userID), zap.Int64("token", next))
+		return next, true, nil
+	}
+
+	return 0, false, fmt.Errorf("failed to acquire cart lock token after %d attempts", maxLockTokenRetries)
 }
 
-// ReleaseLock - with Dapr State Management, ETag-based concurrency replaces distributed locks
-// This method is kept for interface compatibility
-func (r *DaprCartRepository) ReleaseLock(ctx context.Context, userID string) error {
-	// With Dapr State Management, we don't need explicit locks
+// ReleaseLockWithToken is a no-op kept to satisfy CartRepository: with Dapr
+// State Management, SaveCartWithToken's check
+// against the token AcquireLockWithToken recorded is what actually rejects
+// a stale holder's write, not this release call.
+func (r *DaprCartRepository) ReleaseLockWithToken(ctx context.Context, userID string, token int64) error {
 	r.logger.Debug("Lock release not needed with Dapr ETag-based concurrency",
-		zap.String("userID", userID))
+		zap.String("userID", userID), zap.Int64("token", token))
 	return nil
 }
 
-// GetAllCartKeys retrieves all cart keys for cleanup operations
-// Note: This is a Dapr limitation - bulk query is not directly supported
-// For production, consider using a separate metadata store or scheduled cleanup
+// RefreshLock re-saves userID's lock-token entry with a new TTL, CAS-guarded
+// on the current value still equalling token, so a holder whose read-modify-
+// write cycle runs longer than its original lease doesn't lose the lock to
+// expiry mid-request. Returns models.ErrLockTokenMismatch if a newer
+// AcquireLockWithToken call has since superseded token.
+func (r *DaprCartRepository) RefreshLock(ctx context.Context, userID string, token int64, ttl time.Duration) error {
+	key := r.getLockTokenKey(userID)
+
+	item, err := r.client.GetState(ctx, r.stateStoreName, key, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read cart lock token: %w", err)
+	}
+
+	current, err := strconv.ParseInt(string(item.Value), 10, 64)
+	if err != nil || current != token {
+		return models.ErrLockTokenMismatch
+	}
+
+	metadata := map[string]string{"ttlInSeconds": fmt.Sprintf("%d", int(ttl.Seconds()))}
+	if err := r.client.SaveStateWithETag(ctx, r.stateStoreName, key, item.Value, item.Etag, metadata); err != nil {
+		if isETagConflict(err) {
+			return models.ErrLockTokenMismatch
+		}
+		return fmt.Errorf("failed to refresh cart lock token: %w", err)
+	}
+
+	return nil
+}
+
+// SaveCartWithToken saves cart only if token still matches the current
+// value recorded at userID's lock-token key, returning
+// models.ErrLockTokenMismatch if a later AcquireLockWithToken call has
+// since issued a newer token (e.g. this holder's lease expired mid-request).
+//
+// The token check and the cart save are one ExecuteStateTransaction: the
+// lock-token op re-writes its own unchanged value but conditions on the
+// ETag read just above, so the whole transaction is rejected if a newer
+// AcquireLockWithToken/RefreshLock call has touched that key since - closing
+// the gap a separate GetState-then-SaveCart would leave between the check
+// and the save for another holder to acquire a new token and save into.
+func (r *DaprCartRepository) SaveCartWithToken(ctx context.Context, cart *models.Cart, token int64) error {
+	tokenKey := r.getLockTokenKey(cart.UserID)
+
+	item, err := r.client.GetState(ctx, r.stateStoreName, tokenKey, nil)
+	if err != nil {
+		return fmt.Errorf("failed to check cart lock token: %w", err)
+	}
+
+	current, err := strconv.ParseInt(string(item.Value), 10, 64)
+	if err != nil || current != token {
+		return models.ErrLockTokenMismatch
+	}
+
+	data, err := json.Marshal(cart)
+	if err != nil {
+		r.logger.Error("Failed to marshal cart data",
+			zap.String("userID", cart.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to marshal cart: %w", err)
+	}
+
+	ttl := int(time.Until(cart.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		ttl = 60
+	}
+
+	ops := []*dapr.StateOperation{
+		{
+			Type: dapr.StateOperationTypeUpsert,
+			Item: &dapr.SetStateItem{
+				Key:      r.getCartKey(cart.UserID),
+				Value:    data,
+				Metadata: map[string]string{"ttlInSeconds": fmt.Sprintf("%d", ttl)},
+			},
+		},
+		{
+			Type: dapr.StateOperationTypeUpsert,
+			Item: &dapr.SetStateItem{
+				Key:   tokenKey,
+				Value: item.Value,
+				Etag:  &dapr.ETag{Value: item.Etag},
+			},
+		},
+	}
+	if indexOp, err := r.indexOpForSave(ctx, cart.UserID); err != nil {
+		r.logger.Warn("Failed to prepare cart index update, saving cart without it",
+			zap.String("userID", cart.UserID), zap.Error(err))
+	} else if indexOp != nil {
+		ops = append(ops, indexOp)
+	}
+
+	if err := r.client.ExecuteStateTransaction(ctx, r.stateStoreName, nil, ops); err != nil {
+		if isETagConflict(err) {
+			return models.ErrLockTokenMismatch
+		}
+		r.logger.Error("Failed to save cart with lock token",
+			zap.String("userID", cart.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save cart: %w", err)
+	}
+
+	r.logger.Debug("Cart saved successfully with fencing token",
+		zap.String("userID", cart.UserID), zap.Int("ttlSeconds", ttl))
+
+	return nil
+}
+
+// DeleteCartWithToken deletes cart only if token still matches the current
+// value recorded at userID's lock-token key, the same fencing guard
+// SaveCartWithToken gives writes, and for the same reason: folded into one
+// ExecuteStateTransaction with a re-write of the lock-token key conditioned
+// on its ETag, so a newer AcquireLockWithToken/RefreshLock call rejects the
+// whole transaction instead of letting a stale holder's delete land.
+func (r *DaprCartRepository) DeleteCartWithToken(ctx context.Context, userID string, token int64) error {
+	tokenKey := r.getLockTokenKey(userID)
+
+	item, err := r.client.GetState(ctx, r.stateStoreName, tokenKey, nil)
+	if err != nil {
+		return fmt.Errorf("failed to check cart lock token: %w", err)
+	}
+
+	current, err := strconv.ParseInt(string(item.Value), 10, 64)
+	if err != nil || current != token {
+		return models.ErrLockTokenMismatch
+	}
+
+	ops := []*dapr.StateOperation{
+		{Type: dapr.StateOperationTypeDelete, Item: &dapr.SetStateItem{Key: r.getCartKey(userID)}},
+		{
+			Type: dapr.StateOperationTypeUpsert,
+			Item: &dapr.SetStateItem{
+				Key:   tokenKey,
+				Value: item.Value,
+				Etag:  &dapr.ETag{Value: item.Etag},
+			},
+		},
+	}
+	if indexOp, err := r.indexOpForDelete(ctx, userID); err != nil {
+		r.logger.Warn("Failed to prepare cart index update, deleting cart without it",
+			zap.String("userID", userID), zap.Error(err))
+	} else if indexOp != nil {
+		ops = append(ops, indexOp)
+	}
+
+	if err := r.client.ExecuteStateTransaction(ctx, r.stateStoreName, nil, ops); err != nil {
+		if isETagConflict(err) {
+			return models.ErrLockTokenMismatch
+		}
+		r.logger.Error("Failed to delete cart with lock token",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+
+	r.logger.Debug("Cart deleted successfully with fencing token", zap.String("userID", userID))
+	return nil
+}
+
+// SaveCartWithOutbox saves cart and writes records under a single Dapr
+// state transaction (ExecuteStateTransaction), so a crash right after this
+// call returns can never leave the cart saved with its domain events lost.
+func (r *DaprCartRepository) SaveCartWithOutbox(ctx context.Context, cart *models.Cart, records []models.OutboxRecord) error {
+	cartData, err := json.Marshal(cart)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cart: %w", err)
+	}
+
+	ttl := int(time.Until(cart.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		ttl = 60
+	}
+
+	ops := []*dapr.StateOperation{
+		{
+			Type: dapr.StateOperationTypeUpsert,
+			Item: &dapr.SetStateItem{
+				Key:      r.getCartKey(cart.UserID),
+				Value:    cartData,
+				Metadata: map[string]string{"ttlInSeconds": fmt.Sprintf("%d", ttl)},
+			},
+		},
+	}
+
+	for _, record := range records {
+		recordData, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox record %s: %w", record.ID, err)
+		}
+		ops = append(ops, &dapr.StateOperation{
+			Type: dapr.StateOperationTypeUpsert,
+			Item: &dapr.SetStateItem{
+				Key:   r.getOutboxKey(record.ID),
+				Value: recordData,
+			},
+		})
+	}
+
+	if indexOp, err := r.indexOpForSave(ctx, cart.UserID); err != nil {
+		r.logger.Warn("Failed to prepare cart index update, saving cart with outbox without it",
+			zap.String("userID", cart.UserID), zap.Error(err))
+	} else if indexOp != nil {
+		ops = append(ops, indexOp)
+	}
+
+	if err := r.client.ExecuteStateTransaction(ctx, r.stateStoreName, nil, ops); err != nil {
+		r.logger.Error("Failed to save cart with outbox",
+			zap.String("userID", cart.UserID), zap.Error(err))
+		return fmt.Errorf("failed to save cart with outbox: %w", err)
+	}
+
+	r.logger.Debug("Cart saved with outbox records",
+		zap.String("userID", cart.UserID), zap.Int("recordCount", len(records)))
+	return nil
+}
+
+// EnqueueOutboxRecords writes records to their own outbox keys without
+// touching any cart key, for callers with no cart content to save alongside
+// them (see CartRepository.EnqueueOutboxRecords).
+func (r *DaprCartRepository) EnqueueOutboxRecords(ctx context.Context, records []models.OutboxRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ops := make([]*dapr.StateOperation, 0, len(records))
+	for _, record := range records {
+		recordData, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox record %s: %w", record.ID, err)
+		}
+		ops = append(ops, &dapr.StateOperation{
+			Type: dapr.StateOperationTypeUpsert,
+			Item: &dapr.SetStateItem{
+				Key:   r.getOutboxKey(record.ID),
+				Value: recordData,
+			},
+		})
+	}
+
+	if err := r.client.ExecuteStateTransaction(ctx, r.stateStoreName, nil, ops); err != nil {
+		r.logger.Error("Failed to enqueue outbox records", zap.Error(err))
+		return fmt.Errorf("failed to enqueue outbox records: %w", err)
+	}
+	return nil
+}
+
+// ListPendingOutboxRecords returns up to limit outbox records with
+// published == false, using the same Dapr State Query API (alpha1) as
+// FindExpiredCartUserIDs.
+func (r *DaprCartRepository) ListPendingOutboxRecords(ctx context.Context, limit int) ([]models.OutboxRecord, error) {
+	query, err := json.Marshal(pendingOutboxQuery{
+		Filter: pendingOutboxFilter{EQ: map[string]bool{"published": false}},
+		Page:   queryPage{Limit: limit},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pending outbox query: %w", err)
+	}
+
+	resp, err := r.client.QueryStateAlpha1(ctx, r.stateStoreName, string(query), nil)
+	if err != nil {
+		r.logger.Error("Failed to query pending outbox records", zap.Error(err))
+		return nil, fmt.Errorf("failed to query pending outbox records: %w", err)
+	}
+
+	records := make([]models.OutboxRecord, 0, len(resp.Results))
+	for _, item := range resp.Results {
+		if item.Error != "" {
+			r.logger.Warn("Skipping pending outbox query result with error",
+				zap.String("key", item.Key), zap.String("error", item.Error))
+			continue
+		}
+		var record models.OutboxRecord
+		if err := json.Unmarshal(item.Value, &record); err != nil {
+			r.logger.Warn("Skipping unparseable outbox record",
+				zap.String("key", item.Key), zap.Error(err))
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// MarkOutboxRecordPublished reads recordID's outbox entry, sets Published,
+// and writes it back with its current ETag so a concurrent dispatcher
+// replica (or a retried poll) can't double-mark it.
+func (r *DaprCartRepository) MarkOutboxRecordPublished(ctx context.Context, recordID string) error {
+	key := r.getOutboxKey(recordID)
+
+	item, err := r.client.GetState(ctx, r.stateStoreName, key, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read outbox record: %w", err)
+	}
+	if item.Value == nil || len(item.Value) == 0 {
+		return nil
+	}
+
+	var record models.OutboxRecord
+	if err := json.Unmarshal(item.Value, &record); err != nil {
+		return fmt.Errorf("failed to unmarshal outbox record: %w", err)
+	}
+
+	now := time.Now().UTC()
+	record.Published = true
+	record.PublishedAt = &now
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox record: %w", err)
+	}
+
+	if err := r.client.SaveStateWithETag(ctx, r.stateStoreName, key, data, item.Etag, nil); err != nil {
+		if isETagConflict(err) {
+			// Another dispatch pass already marked it; nothing left to do.
+			return nil
+		}
+		return fmt.Errorf("failed to mark outbox record published: %w", err)
+	}
+
+	return nil
+}
+
+// pendingOutboxQuery is the Dapr state query document ListPendingOutboxRecords
+// builds, mirroring expiredCartQuery's shape for a boolean-equality filter.
+type pendingOutboxQuery struct {
+	Filter pendingOutboxFilter `json:"filter"`
+	Page   queryPage           `json:"page,omitempty"`
+}
+
+type pendingOutboxFilter struct {
+	EQ map[string]bool `json:"EQ"`
+}
+
+// GetAllCartKeys returns every userID with a stored cart by walking the
+// secondary cart-key index (see ListAllCartKeys); see its doc comment for
+// the tradeoff this index makes against pure TTL-based cleanup.
 func (r *DaprCartRepository) GetAllCartKeys(ctx context.Context) ([]string, error) {
-	r.logger.Warn("GetAllCartKeys is not efficiently supported by Dapr State Management",
-		zap.String("recommendation", "Use TTL-based expiration instead"))
-	// Return empty slice - rely on Dapr TTL for automatic cleanup
-	return []string{}, nil
+	return r.ListAllCartKeys(ctx)
+}
+
+// ListCartKeys returns the userIDs recorded in index shard `shard`'s entry.
+func (r *DaprCartRepository) ListCartKeys(ctx context.Context, shard int) ([]string, error) {
+	return r.readIndexShard(ctx, r.shardIndexKey(shard))
+}
+
+// ListAllCartKeys returns every userID recorded across all index shards, by
+// reading each shard's entry in turn.
+//
+// Dapr state TTL (see SaveCart) already expires carts without this index;
+// what it can't do is enumerate which keys exist, since most state-store
+// components don't support listing keys by prefix. This index exists so
+// GetAllCartKeys and internal/purge.Sweeper's cleanup have something to walk
+// on those components: it trades a little write-path overhead (and a small
+// window where concurrent saves to the same shard can race, see
+// indexOpForSave) for the ability to actually enumerate and sweep expired
+// carts, rather than relying solely on the state store's own TTL eviction
+// or FindExpiredCartUserIDs's Query API pushdown (which remains the
+// cheaper, preferred mechanism on components that support it).
+func (r *DaprCartRepository) ListAllCartKeys(ctx context.Context) ([]string, error) {
+	var all []string
+	for shard := 0; shard < r.indexShardCount; shard++ {
+		ids, err := r.readIndexShard(ctx, r.shardIndexKey(shard))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cart index shard %d: %w", shard, err)
+		}
+		all = append(all, ids...)
+	}
+	return all, nil
 }
 
 // GetCartTTL gets the remaining TTL for a cart
@@ -203,7 +782,191 @@ func (r *DaprCartRepository) GetCartTTL(ctx context.Context, userID string) (tim
 	return ttl, nil
 }
 
+// FindExpiredCartUserIDs returns up to limit user IDs whose stored cart has
+// already expired (expiresAt before cutoff), using Dapr's State Query API
+// (alpha1). It pushes the expiry filter down to the state store, so it's
+// cheaper than GetAllCartKeys's walk-then-check-each-cart approach, but it
+// only works against components that support querying (e.g. Redis with the
+// RediSearch module, or MongoDB/CosmosDB). internal/purge.Sweeper uses this
+// as its scan. When the query fails (e.g. on a component that doesn't
+// support QueryStateAlpha1), it falls back to walking ListAllCartKeys and
+// returning up to limit carts that GetCart reports as expired; that walk
+// judges expiry against the current time rather than cutoff, and GetCart
+// deletes each expired cart as it reads it.
+func (r *DaprCartRepository) FindExpiredCartUserIDs(ctx context.Context, cutoff time.Time, limit int) ([]string, error) {
+	query, err := json.Marshal(expiredCartQuery{
+		Filter: queryFilter{LTE: map[string]string{"expiresAt": cutoff.UTC().Format(time.RFC3339)}},
+		Page:   queryPage{Limit: limit},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expired cart query: %w", err)
+	}
+
+	resp, err := r.client.QueryStateAlpha1(ctx, r.stateStoreName, string(query), nil)
+	if err != nil {
+		r.logger.Warn("Failed to query expired carts, walking the cart index instead", zap.Error(err))
+		allIDs, listErr := r.ListAllCartKeys(ctx)
+		if listErr != nil {
+			return nil, fmt.Errorf("failed to query expired carts: %w", err)
+		}
+		expired := make([]string, 0, limit)
+		for _, userID := range allIDs {
+			if len(expired) >= limit {
+				break
+			}
+			if _, getErr := r.GetCart(ctx, userID); getErr == models.ErrCartExpired {
+				expired = append(expired, userID)
+			}
+		}
+		return expired, nil
+	}
+
+	userIDs := make([]string, 0, len(resp.Results))
+	for _, item := range resp.Results {
+		if item.Error != "" {
+			r.logger.Warn("Skipping expired cart query result with error",
+				zap.String("key", item.Key), zap.String("error", item.Error))
+			continue
+		}
+		userIDs = append(userIDs, strings.TrimPrefix(item.Key, cartKeyPrefix))
+	}
+
+	return userIDs, nil
+}
+
+// expiredCartQuery is the Dapr state query document built by
+// FindExpiredCartUserIDs. See https://docs.dapr.io/developing-applications/building-blocks/state-management/query-state-store/
+type expiredCartQuery struct {
+	Filter queryFilter `json:"filter"`
+	Page   queryPage   `json:"page,omitempty"`
+}
+
+type queryFilter struct {
+	LTE map[string]string `json:"LTE"`
+}
+
+type queryPage struct {
+	Limit int `json:"limit,omitempty"`
+}
+
 // Helper methods
 func (r *DaprCartRepository) getCartKey(userID string) string {
 	return cartKeyPrefix + userID
 }
+
+func (r *DaprCartRepository) getLockTokenKey(userID string) string {
+	return lockTokenKeyPrefix + userID
+}
+
+func (r *DaprCartRepository) getOutboxKey(recordID string) string {
+	return outboxKeyPrefix + recordID
+}
+
+func (r *DaprCartRepository) shardIndexKey(shard int) string {
+	return fmt.Sprintf("%s%d", cartIndexKeyPrefix, shard)
+}
+
+func (r *DaprCartRepository) cartIndexKey(userID string) string {
+	return r.shardIndexKey(shardForUserID(userID, r.indexShardCount))
+}
+
+// readIndexShard reads and unmarshals a cart-key index shard entry, which
+// holds a JSON array of userIDs. A shard with no entry yet reads as an
+// empty slice rather than an error.
+func (r *DaprCartRepository) readIndexShard(ctx context.Context, key string) ([]string, error) {
+	item, err := r.client.GetState(ctx, r.stateStoreName, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cart index shard: %w", err)
+	}
+	if len(item.Value) == 0 {
+		return nil, nil
+	}
+
+	var userIDs []string
+	if err := json.Unmarshal(item.Value, &userIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cart index shard: %w", err)
+	}
+	return userIDs, nil
+}
+
+// indexOpForSave returns the StateOperation that records userID in its
+// shard's index entry, for inclusion in the same transaction as a cart
+// upsert, or nil if userID is already recorded there.
+//
+// This isn't itself conditioned on the shard entry's ETag: two concurrent
+// saves for different users hashing to the same shard can race and
+// overwrite each other's addition. Since the index only drives best-effort
+// cleanup scanning rather than cart content, a lost addition just means
+// that userID is briefly absent from GetAllCartKeys until its next save —
+// the same non-ETag-conditioned tradeoff SaveCartWithOutbox already makes
+// for outbox entries.
+func (r *DaprCartRepository) indexOpForSave(ctx context.Context, userID string) (*dapr.StateOperation, error) {
+	key := r.cartIndexKey(userID)
+	userIDs, err := r.readIndexShard(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range userIDs {
+		if id == userID {
+			return nil, nil
+		}
+	}
+
+	data, err := json.Marshal(append(userIDs, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cart index shard: %w", err)
+	}
+	return &dapr.StateOperation{
+		Type: dapr.StateOperationTypeUpsert,
+		Item: &dapr.SetStateItem{Key: key, Value: data},
+	}, nil
+}
+
+// indexOpForDelete returns the StateOperation that removes userID from its
+// shard's index entry, for inclusion in the same transaction as a cart
+// delete, or nil if userID isn't recorded there. See indexOpForSave for the
+// same race tradeoff, applied here to removals.
+func (r *DaprCartRepository) indexOpForDelete(ctx context.Context, userID string) (*dapr.StateOperation, error) {
+	key := r.cartIndexKey(userID)
+	userIDs, err := r.readIndexShard(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := userIDs[:0]
+	found := false
+	for _, id := range userIDs {
+		if id == userID {
+			found = true
+			continue
+		}
+		remaining = append(remaining, id)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(remaining)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cart index shard: %w", err)
+	}
+	return &dapr.StateOperation{
+		Type: dapr.StateOperationTypeUpsert,
+		Item: &dapr.SetStateItem{Key: key, Value: data},
+	}, nil
+}
+
+// addToIndex is indexOpForSave's standalone equivalent for callers (like
+// SaveCartWithETag) that can't fold the index update into their own cart
+// write transaction; see indexOpForSave for the race tradeoff this makes.
+func (r *DaprCartRepository) addToIndex(ctx context.Context, userID string) error {
+	op, err := r.indexOpForSave(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if op == nil {
+		return nil
+	}
+	return r.client.SaveState(ctx, r.stateStoreName, op.Item.Key, op.Item.Value, op.Item.Metadata)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/storage"
+	"go.uber.org/zap"
+)
+
+// DurableCartRepository wraps a CartRepository so every path that persists
+// or reads authoritative cart content - GetCart/SaveCart/DeleteCart, plus
+// the token/ETag-gated mutations AddItem/UpdateItem/RemoveItem actually use
+// - goes through a storage.CachedStore (a durable primary store, e.g.
+// Postgres, with inner itself serving as the cache tier via
+// storage.NewRedisStore) in addition to inner, so carts survive a Redis
+// restart or eviction regardless of which save path a caller takes.
+//
+// inner remains the source of truth for concurrency control: it owns the
+// lock/fencing-token and ETag primitives those gated methods check, and
+// store has no compare-and-swap of its own to enforce instead. So each
+// gated method below still does its CAS against inner first and only then
+// write-throughs the result to store, best-effort, the same tradeoff
+// SaveCart's plain write-through already makes for store.Save failures.
+// Lock/TTL/outbox/key-listing methods have no durable-store equivalent and
+// forward to inner untouched via embedding.
+type DurableCartRepository struct {
+	CartRepository
+	store  *storage.CachedStore
+	logger *zap.Logger
+}
+
+// NewDurableCartRepository wraps inner, routing GetCart/SaveCart/DeleteCart
+// and the token/ETag-gated mutations through store in addition to inner.
+func NewDurableCartRepository(inner CartRepository, store *storage.CachedStore, logger *zap.Logger) *DurableCartRepository {
+	return &DurableCartRepository{CartRepository: inner, store: store, logger: logger}
+}
+
+func (d *DurableCartRepository) GetCart(ctx context.Context, userID string) (*models.Cart, error) {
+	return d.store.Get(ctx, userID)
+}
+
+func (d *DurableCartRepository) SaveCart(ctx context.Context, cart *models.Cart) error {
+	return d.store.Save(ctx, cart)
+}
+
+func (d *DurableCartRepository) DeleteCart(ctx context.Context, userID string) error {
+	return d.store.Delete(ctx, userID)
+}
+
+// GetCartWithETag reads through inner, since the returned ETag is a token
+// over inner's own stored representation and store has no equivalent. If
+// inner has no record - e.g. a Redis restart evicted it since the last
+// write - it re-seeds inner from store and re-reads, so a caller still gets
+// a cart (and a fresh, valid ETag) instead of a false ErrCartNotFound.
+func (d *DurableCartRepository) GetCartWithETag(ctx context.Context, userID string) (*models.Cart, string, error) {
+	cart, etag, err := d.CartRepository.GetCartWithETag(ctx, userID)
+	if err == nil {
+		return cart, etag, nil
+	}
+	if err != models.ErrCartNotFound {
+		return nil, "", err
+	}
+
+	durableCart, storeErr := d.store.Get(ctx, userID)
+	if storeErr != nil {
+		return nil, "", err
+	}
+	if err := d.CartRepository.SaveCart(ctx, durableCart); err != nil {
+		return nil, "", fmt.Errorf("failed to reseed cart from durable store: %w", err)
+	}
+	return d.CartRepository.GetCartWithETag(ctx, userID)
+}
+
+// SaveCartWithETag CASes against inner - the only place the ETag is
+// meaningful - then write-throughs the result to store so it isn't lost on
+// a Redis restart before its next plain SaveCart/SaveCartWithToken.
+func (d *DurableCartRepository) SaveCartWithETag(ctx context.Context, cart *models.Cart, etag string) error {
+	if err := d.CartRepository.SaveCartWithETag(ctx, cart, etag); err != nil {
+		return err
+	}
+	if err := d.store.Save(ctx, cart); err != nil {
+		d.logger.Warn("Failed to write-through cart to durable store after ETag-gated save",
+			zap.String("userID", cart.UserID), zap.Error(err))
+	}
+	return nil
+}
+
+// SaveCartWithToken CASes against inner - the only place the fencing token
+// is meaningful - then write-throughs the result to store, the same
+// best-effort durability SaveCartWithETag gives its own gated save.
+func (d *DurableCartRepository) SaveCartWithToken(ctx context.Context, cart *models.Cart, token int64) error {
+	if err := d.CartRepository.SaveCartWithToken(ctx, cart, token); err != nil {
+		return err
+	}
+	if err := d.store.Save(ctx, cart); err != nil {
+		d.logger.Warn("Failed to write-through cart to durable store after token-gated save",
+			zap.String("userID", cart.UserID), zap.Error(err))
+	}
+	return nil
+}
+
+// DeleteCartWithToken CASes against inner, then removes the durable record
+// too so a token-gated delete (e.g. TransferCart's source cart) doesn't
+// leave a stale row behind in store.
+func (d *DurableCartRepository) DeleteCartWithToken(ctx context.Context, userID string, token int64) error {
+	if err := d.CartRepository.DeleteCartWithToken(ctx, userID, token); err != nil {
+		return err
+	}
+	if err := d.store.Delete(ctx, userID); err != nil {
+		d.logger.Warn("Failed to delete cart from durable store after token-gated delete",
+			zap.String("userID", userID), zap.Error(err))
+	}
+	return nil
+}
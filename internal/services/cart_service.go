@@ -2,17 +2,46 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/aioutlet/cart-service/internal/cartctx"
 	"github.com/aioutlet/cart-service/internal/config"
+	"github.com/aioutlet/cart-service/internal/events"
+	"github.com/aioutlet/cart-service/internal/middleware"
 	"github.com/aioutlet/cart-service/internal/models"
 	"github.com/aioutlet/cart-service/internal/repository"
+	"github.com/aioutlet/cart-service/internal/webhooks"
 	"github.com/aioutlet/cart-service/pkg/clients"
+	"github.com/aioutlet/cart-service/pkg/lock"
+	"github.com/aioutlet/cart-service/pkg/saga"
+	"github.com/aioutlet/cart-service/pkg/tracing"
+	"github.com/aioutlet/cart-service/pkg/utils"
 	dapr "github.com/dapr/go-sdk/client"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
+// maxMutationRetries bounds how many times a cart mutation reloads and
+// re-applies itself after losing an ETag race to a concurrent writer, before
+// giving up and surfacing the conflict to the caller.
+const maxMutationRetries = 3
+
+// baseMutationRetryDelay is the starting delay saveCartWithRetry backs off
+// by between ETag-conflict retries, doubling each attempt like
+// internal/webhooks.Dispatcher's delivery backoff.
+const baseMutationRetryDelay = 20 * time.Millisecond
+
+// mutationRetryBackoff returns the delay before retry attempt n (1-indexed),
+// doubling from baseMutationRetryDelay.
+func mutationRetryBackoff(attempt int) time.Duration {
+	return baseMutationRetryDelay * time.Duration(math.Pow(2, float64(attempt-1)))
+}
+
 // CartService interface defines cart service operations
 type CartService interface {
 	GetCart(ctx context.Context, userID string) (*models.Cart, error)
@@ -20,33 +49,66 @@ type CartService interface {
 	UpdateItem(ctx context.Context, userID string, productID string, request models.UpdateItemRequest) (*models.Cart, error)
 	RemoveItem(ctx context.Context, userID string, productID string) (*models.Cart, error)
 	ClearCart(ctx context.Context, userID string) error
+	BulkApply(ctx context.Context, ownerID string, ops []models.CartOp) (*models.Cart, []models.OpResult, error)
 	TransferCart(ctx context.Context, fromUserID, toUserID string) (*models.Cart, error)
 	ValidateCart(ctx context.Context, userID string) (*models.Cart, error)
 	GetCartSummary(ctx context.Context, userID string) (*models.CartSummary, error)
+	// Limits returns the live, atomically-swappable cart limits this
+	// service reads through (see config.LiveCartLimits).
+	Limits() *config.LiveCartLimits
+	// ProductCacheMetrics returns the cumulative hit/miss/coalesced counts
+	// of the clients.CachedProductClient this service looks products up
+	// through, for the /metrics endpoint.
+	ProductCacheMetrics() clients.CachedProductClientMetrics
 }
 
 // cartService implements CartService interface
 type cartService struct {
-	repo           repository.CartRepository
-	productClient  clients.ProductClient
+	repo            repository.CartRepository
+	productClient   clients.ProductClient
 	inventoryClient clients.InventoryClient
-	config         *config.Config
-	logger         *zap.Logger
+	locker          lock.Locker
+	saga            saga.Runner
+	config          *config.Config
+	limits          *config.LiveCartLimits
+	logger          *zap.Logger
+	publisher       webhooks.Publisher
+	metrics         *tracing.CartMetrics
 }
 
-// NewCartService creates a new cart service
+// NewCartService creates a new cart service. meterProvider is used to build
+// the OTel instruments in tracing.CartMetrics; pass nil to fall back to the
+// global MeterProvider (a no-op until an OTel metrics SDK sets one).
 func NewCartService(
 	repo repository.CartRepository,
 	daprClient dapr.Client,
 	cfg *config.Config,
 	logger *zap.Logger,
+	publisher webhooks.Publisher,
+	meterProvider metric.MeterProvider,
 ) CartService {
+	metrics, err := tracing.NewCartMetrics(meterProvider)
+	if err != nil {
+		panic(err)
+	}
+
 	return &cartService{
-		repo:            repo,
-		productClient:   clients.NewProductClient(daprClient, logger),
+		repo: repository.NewCachedCartRepository(repo, cfg.CartCache.TTL),
+		productClient: clients.NewCachedProductClient(
+			clients.NewProductClient(daprClient, logger),
+			cfg.ProductCache.TTL,
+			cfg.ProductCache.MaxSize,
+			cfg.ProductCache.BatchWindow,
+			logger,
+		),
 		inventoryClient: clients.NewInventoryClient(daprClient, logger),
+		locker:          lock.NewCartLocker(daprClient, lock.Config(cfg.Lock), logger),
+		saga:            saga.NewOrchestrator(daprClient, cfg.Dapr.StateStoreName, logger),
 		config:          cfg,
+		limits:          config.NewLiveCartLimits(cfg.Cart),
 		logger:          logger,
+		publisher:       publisher,
+		metrics:         metrics,
 	}
 }
 
@@ -55,28 +117,283 @@ func NewCartServiceWithClients(
 	repo repository.CartRepository,
 	productClient clients.ProductClient,
 	inventoryClient clients.InventoryClient,
+	locker lock.Locker,
+	sagaRunner saga.Runner,
 	cfg *config.Config,
 	logger *zap.Logger,
+	publisher webhooks.Publisher,
 ) CartService {
+	metrics, err := tracing.NewCartMetrics(nil)
+	if err != nil {
+		panic(err)
+	}
+
 	return &cartService{
 		repo:            repo,
 		productClient:   productClient,
 		inventoryClient: inventoryClient,
+		locker:          locker,
+		saga:            sagaRunner,
 		config:          cfg,
+		limits:          config.NewLiveCartLimits(cfg.Cart),
 		logger:          logger,
+		publisher:       publisher,
+		metrics:         metrics,
+	}
+}
+
+// Limits returns the live, atomically-swappable cart limits this service
+// reads through, so main.go can bind it to a dynconfig.Provider after
+// construction (see config.LiveCartLimits.Bind).
+func (s *cartService) Limits() *config.LiveCartLimits {
+	return s.limits
+}
+
+// productCacheMetricsProvider is satisfied by clients.CachedProductClient;
+// ProductCacheMetrics type-asserts against it rather than widening
+// clients.ProductClient, since test doubles passed to
+// NewCartServiceWithClients have no cache to report on.
+type productCacheMetricsProvider interface {
+	Metrics() clients.CachedProductClientMetrics
+}
+
+func (s *cartService) ProductCacheMetrics() clients.CachedProductClientMetrics {
+	if provider, ok := s.productClient.(productCacheMetricsProvider); ok {
+		return provider.Metrics()
+	}
+	return clients.CachedProductClientMetrics{}
+}
+
+// publish emits a webhook event for a cart mutation if a publisher is
+// configured; nil publishers (e.g. in tests) are a no-op.
+func (s *cartService) publish(ctx context.Context, eventType models.WebhookEventType, userID string, cart *models.Cart) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(ctx, models.WebhookEvent{
+		Type:          eventType,
+		UserID:        userID,
+		CorrelationID: middleware.CorrelationIDFromContext(ctx),
+		Cart:          cart,
+		OccurredAt:    time.Now().UTC(),
+	})
+}
+
+// recordOutboxEvent writes a models.OutboxRecord for eventType alongside
+// cart's current state via CartRepository.SaveCartWithOutbox, so
+// internal/events.OutboxDispatcher can deliver it even if the process
+// crashes right after this call returns. By the time this runs, the
+// mutation's authoritative save has already succeeded (via saveCartWithRetry
+// or SaveCartWithToken), so re-saving cart's already-persisted content here
+// is harmless — this call exists purely to get the event recorded
+// transactionally alongside it. A nil publisher-less repo (e.g. in tests
+// that don't care about outbox events) still requires this to be called;
+// errors are logged rather than surfaced, matching s.publish's
+// don't-fail-the-request treatment of the webhook subsystem.
+func (s *cartService) recordOutboxEvent(ctx context.Context, eventType models.OutboxEventType, userID string, cart *models.Cart) {
+	record, err := events.NewRecord(eventType, userID, cart)
+	if err != nil {
+		s.logger.Error("Failed to build outbox event",
+			zap.String("userID", userID), zap.String("eventType", string(eventType)), zap.Error(err))
+		return
+	}
+	if err := s.repo.SaveCartWithOutbox(ctx, cart, []models.OutboxRecord{record}); err != nil {
+		s.logger.Error("Failed to record outbox event",
+			zap.String("userID", userID), zap.String("eventType", string(eventType)), zap.Error(err))
+	}
+}
+
+// cartLimits resolves the item-count and per-item quantity limits for the
+// given pricing experiment (from cartctx baggage), falling back to the
+// configured defaults when experiment is empty or unrecognized.
+func (s *cartService) cartLimits(experiment string) (maxItems, maxItemQty int) {
+	maxItems, maxItemQty, _, _ = s.limits.Snapshot()
+
+	if experiment == "" {
+		return maxItems, maxItemQty
+	}
+	if override, ok := s.config.Cart.ExperimentMaxItemQty[experiment]; ok {
+		maxItemQty = override
+	}
+	return maxItems, maxItemQty
+}
+
+// defaultTTL returns the TTL new carts are created with, read through the
+// same live snapshot as cartLimits.
+func (s *cartService) defaultTTL() time.Duration {
+	_, _, defaultTTL, _ := s.limits.Snapshot()
+	return defaultTTL
+}
+
+// maxItemQty returns the current per-item quantity limit, unaffected by any
+// pricing experiment override (see cartLimits for the experiment-aware path).
+func (s *cartService) maxItemQty() int {
+	_, maxItemQty, _, _ := s.limits.Snapshot()
+	return maxItemQty
+}
+
+// maxItems returns the current cart item-count limit.
+func (s *cartService) maxItems() int {
+	maxItems, _, _, _ := s.limits.Snapshot()
+	return maxItems
+}
+
+// checkAvailabilityBatch resolves availability for several SKUs in one
+// round-trip when there's more than one, falling back to one
+// CheckAvailability call per SKU when the batch endpoint isn't available yet
+// (or there's only a single item, which isn't worth batching). Like the
+// existing single-SKU call sites, an inventory failure is logged and treated
+// as available rather than blocking the cart operation.
+func (s *cartService) checkAvailabilityBatch(ctx context.Context, items []clients.SKUQty) map[string]bool {
+	if len(items) <= 1 {
+		return s.checkAvailabilityPerSKU(ctx, items)
+	}
+
+	result, err := s.inventoryClient.CheckAvailabilityBatch(ctx, items)
+	if err == nil {
+		return result
+	}
+	if !errors.Is(err, clients.ErrBatchEndpointUnavailable) {
+		s.logger.Warn("Failed to batch-check inventory availability, falling back to per-SKU checks", zap.Error(err))
+	}
+	return s.checkAvailabilityPerSKU(ctx, items)
+}
+
+func (s *cartService) checkAvailabilityPerSKU(ctx context.Context, items []clients.SKUQty) map[string]bool {
+	result := make(map[string]bool, len(items))
+	for _, item := range items {
+		available, err := s.inventoryClient.CheckAvailability(ctx, item.SKU, item.Quantity)
+		if err != nil {
+			s.logger.Warn("Failed to check inventory, allowing operation",
+				zap.String("sku", item.SKU), zap.Error(err))
+			available = true
+		}
+		result[item.SKU] = available
+	}
+	return result
+}
+
+// availableQuantitiesBatch resolves available quantities for several SKUs in
+// one round-trip, with the same batch/fallback/single-item behavior as
+// checkAvailabilityBatch.
+func (s *cartService) availableQuantitiesBatch(ctx context.Context, skus []string) map[string]int {
+	if len(skus) <= 1 {
+		return s.availableQuantitiesPerSKU(ctx, skus)
+	}
+
+	result, err := s.inventoryClient.GetAvailableQuantitiesBatch(ctx, skus)
+	if err == nil {
+		return result
+	}
+	if !errors.Is(err, clients.ErrBatchEndpointUnavailable) {
+		s.logger.Warn("Failed to batch-fetch available quantities, falling back to per-SKU calls", zap.Error(err))
+	}
+	return s.availableQuantitiesPerSKU(ctx, skus)
+}
+
+func (s *cartService) availableQuantitiesPerSKU(ctx context.Context, skus []string) map[string]int {
+	result := make(map[string]int, len(skus))
+	for _, sku := range skus {
+		qty, err := s.inventoryClient.GetAvailableQuantity(ctx, sku)
+		if err != nil {
+			s.logger.Warn("Failed to get available quantity, skipping adjustment",
+				zap.String("sku", sku), zap.Error(err))
+			continue
+		}
+		result[sku] = qty
+	}
+	return result
+}
+
+// lockOwnerID derives a distributed-lock owner ID from the request's
+// correlation ID, so a stuck lock is traceable back to the request that
+// holds it; background callers with no correlation ID fall back to a
+// synthetic per-call owner.
+func lockOwnerID(ctx context.Context) string {
+	if id := middleware.CorrelationIDFromContext(ctx); id != "" {
+		return id
+	}
+	return fmt.Sprintf("cart-service-%d", time.Now().UnixNano())
+}
+
+// acquireLock wraps s.locker.Acquire, recording how long it took into
+// s.metrics.LockAcquireDuration so lock contention shows up on a dashboard
+// before it shows up as request timeouts.
+func (s *cartService) acquireLock(ctx context.Context, userID, ownerID string) (*lock.Lock, error) {
+	start := time.Now()
+	heldLock, err := s.locker.Acquire(ctx, userID, ownerID)
+	s.metrics.LockAcquireDuration.Record(ctx, time.Since(start).Seconds())
+	return heldLock, err
+}
+
+// getOrCreateCartWithETag loads userID's cart along with its ETag, mirroring
+// GetCart's auto-create behavior for callers that need ETag-based
+// optimistic-concurrency saves afterwards.
+func (s *cartService) getOrCreateCartWithETag(ctx context.Context, userID string) (*models.Cart, string, error) {
+	cart, etag, err := s.repo.GetCartWithETag(ctx, userID)
+	if err == nil {
+		return cart, etag, nil
+	}
+	if err != models.ErrCartNotFound {
+		return nil, "", err
+	}
+
+	cart = models.NewCart(userID, s.defaultTTL())
+	if err := s.repo.SaveCart(ctx, cart); err != nil {
+		return nil, "", fmt.Errorf("failed to create new cart: %w", err)
+	}
+	s.logger.Info("Created new cart", zap.String("userID", userID))
+
+	return s.repo.GetCartWithETag(ctx, userID)
+}
+
+// saveCartWithRetry applies mutate to cart and persists it via ETag-based
+// optimistic concurrency, reloading the cart and re-applying mutate up to
+// maxMutationRetries times if a concurrent writer saved first, instead of
+// failing the request on the first lost race.
+func (s *cartService) saveCartWithRetry(ctx context.Context, userID string, cart *models.Cart, etag string, mutate func(cart *models.Cart) error) (*models.Cart, error) {
+	for attempt := 0; ; attempt++ {
+		if err := mutate(cart); err != nil {
+			return nil, err
+		}
+
+		err := s.repo.SaveCartWithETag(ctx, cart, etag)
+		if err == nil {
+			return cart, nil
+		}
+		if !errors.Is(err, models.ErrCartConflict) || attempt >= maxMutationRetries {
+			return nil, fmt.Errorf("failed to save cart: %w", err)
+		}
+
+		s.logger.Debug("Cart save conflicted with a concurrent writer, reloading and retrying",
+			zap.String("userID", userID), zap.Int("attempt", attempt))
+
+		select {
+		case <-time.After(mutationRetryBackoff(attempt + 1)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		cart, etag, err = s.repo.GetCartWithETag(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
 	}
 }
 
 // GetCart retrieves a cart for a user
-func (s *cartService) GetCart(ctx context.Context, userID string) (*models.Cart, error) {
-	s.logger.Debug("Getting cart", 
+func (s *cartService) GetCart(ctx context.Context, userID string) (cart *models.Cart, err error) {
+	ctx, span := tracing.StartCartSpan(ctx, "get_cart", userID)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	s.logger.Debug("Getting cart",
 		zap.String("userID", userID))
 
-	cart, err := s.repo.GetCart(ctx, userID)
+	cart, err = s.repo.GetCart(ctx, userID)
 	if err != nil {
 		if err == models.ErrCartNotFound {
 			// Create a new empty cart
-			cart = models.NewCart(userID, s.config.Cart.DefaultTTL)
+			cart = models.NewCart(userID, s.defaultTTL())
 			if err := s.repo.SaveCart(ctx, cart); err != nil {
 				s.logger.Error("Failed to save new cart", 
 					zap.String("userID", userID),
@@ -93,6 +410,106 @@ func (s *cartService) GetCart(ctx context.Context, userID string) (*models.Cart,
 	return cart, nil
 }
 
+// reserveStockItem is one SKU/quantity pair reserved by the "reserve_stock"
+// step — one per resolved CartItem, so a bundle's several child SKUs are
+// all recorded under a single step.
+type reserveStockItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// reserveStockData is the saga.Step.Data recorded for the "reserve_stock"
+// step of AddItem's saga, so pkg/saga.Recovery can release an orphaned
+// reservation without the original closures (see ReserveStockCompensation).
+type reserveStockData struct {
+	Items []reserveStockItem `json:"items"`
+}
+
+// ReserveStockCompensation returns the saga.CompensationFunc for the
+// "reserve_stock" step, releasing stock reserved by a saga that never
+// finished. Registered with a saga.Recovery at startup (see cmd/server).
+// It releases every item recorded in Data, best-effort: it keeps going on a
+// per-item failure and returns the first error encountered, since a partial
+// release is still better than none.
+func ReserveStockCompensation(inventoryClient clients.InventoryClient) saga.CompensationFunc {
+	return func(ctx context.Context, data json.RawMessage) error {
+		var d reserveStockData
+		if err := json.Unmarshal(data, &d); err != nil {
+			return fmt.Errorf("failed to unmarshal reserve_stock compensation data: %w", err)
+		}
+		var firstErr error
+		for _, item := range d.Items {
+			if err := inventoryClient.ReleaseStock(ctx, item.SKU, item.Quantity); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+}
+
+// resolveAddItemCartItems expands request into the CartItem(s) AddItem's
+// saga should reserve stock for and add to the cart: a single item for a
+// plain product, a single item resolved against its variant SKU for a
+// configurable product, or one linked child item per BundleChoice for a
+// bundle product, all sharing a freshly generated BundleParentID so
+// UpdateItem/RemoveItem can treat them as one unit.
+func (s *cartService) resolveAddItemCartItems(ctx context.Context, request models.AddItemRequest, productInfo *models.ProductInfo) ([]models.CartItem, error) {
+	now := time.Now().UTC()
+
+	if len(request.BundleConfiguration) > 0 {
+		if !productInfo.IsBundle {
+			return nil, models.ErrProductNotTypeBundle
+		}
+		bundleParentID := uuid.New().String()
+		items := make([]models.CartItem, 0, len(request.BundleConfiguration))
+		for _, choice := range request.BundleConfiguration {
+			childInfo, err := s.productClient.GetProduct(ctx, choice.MarketplaceCode)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get bundle child product %q: %w", choice.MarketplaceCode, err)
+			}
+			items = append(items, models.CartItem{
+				ProductID:      childInfo.ID,
+				ProductName:    childInfo.Name,
+				SKU:            childInfo.SKU,
+				Price:          childInfo.Price,
+				Quantity:       choice.Qty,
+				ImageURL:       childInfo.ImageURL,
+				Category:       childInfo.Category,
+				AddedAt:        now,
+				ReservationID:  uuid.New().String(),
+				BundleParentID: bundleParentID,
+			})
+		}
+		return items, nil
+	}
+
+	if productInfo.IsBundle {
+		return nil, models.ErrNoBundleConfigurationGiven
+	}
+
+	resolved := *productInfo
+	if request.VariantCode != "" {
+		variantSKU := utils.GenerateVariantSKU(productInfo.SKU, "", request.VariantCode)
+		variantInfo, err := s.productClient.GetProduct(ctx, variantSKU)
+		if err != nil {
+			return nil, models.ErrVariantDoNotExist
+		}
+		resolved = *variantInfo
+	}
+
+	return []models.CartItem{{
+		ProductID:     resolved.ID,
+		ProductName:   resolved.Name,
+		SKU:           resolved.SKU,
+		Price:         resolved.Price,
+		Quantity:      request.Quantity,
+		ImageURL:      resolved.ImageURL,
+		Category:      resolved.Category,
+		AddedAt:       now,
+		ReservationID: uuid.New().String(),
+	}}, nil
+}
+
 // AddItem adds an item to the cart
 func (s *cartService) AddItem(ctx context.Context, userID string, request models.AddItemRequest) (*models.Cart, error) {
 	s.logger.Debug("Adding item to cart", 
@@ -100,16 +517,27 @@ func (s *cartService) AddItem(ctx context.Context, userID string, request models
 		zap.String("productID", request.ProductID),
 		zap.Int("quantity", request.Quantity))
 
-	// Acquire lock for cart operations
-	lockAcquired, err := s.repo.AcquireLock(ctx, userID, 30*time.Second)
+	// Acquire the distributed per-user lock for the whole read-modify-write
+	// cycle below.
+	heldLock, err := s.acquireLock(ctx, userID, lockOwnerID(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire cart lock: %w", err)
 	}
-	if !lockAcquired {
-		err := fmt.Errorf("cart is currently being modified, please try again")
-		return nil, err
+	defer s.locker.Release(ctx, heldLock)
+
+	// Also take out a repository-level fencing token alongside the Dapr
+	// lock: if this holder's lease expires mid-request and another process
+	// acquires the lock next, repoToken is superseded and the persist step
+	// below is rejected with models.ErrLockTokenMismatch instead of
+	// silently clobbering the newer holder's write (a Redlock-style guard
+	// against network partitions or process pauses, see pkg/lock).
+	repoToken, tokenOK, err := s.repo.AcquireLockWithToken(ctx, userID, s.config.Lock.LeaseTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire cart lock token: %w", err)
+	}
+	if tokenOK {
+		defer s.repo.ReleaseLockWithToken(ctx, userID, repoToken)
 	}
-	defer s.repo.ReleaseLock(ctx, userID)
 
 	// Get product information
 	productInfo, err := s.productClient.GetProduct(ctx, request.ProductID)
@@ -121,52 +549,175 @@ func (s *cartService) AddItem(ctx context.Context, userID string, request models
 		return nil, fmt.Errorf("product is not available")
 	}
 
-	// Check inventory
-	available, err := s.inventoryClient.CheckAvailability(ctx, request.ProductID, request.Quantity)
+	// Resolve the request into the cart item(s) to add: a single item, a
+	// single item for a resolved variant, or one linked item per bundle
+	// child, each tagged with its own ReservationID up front so a
+	// recovered/compensated reservation can be traced back to the item it
+	// was for.
+	cartItems, err := s.resolveAddItemCartItems(ctx, request, productInfo)
 	if err != nil {
-		s.logger.Warn("Failed to check inventory, allowing operation", 
-			zap.String("productID", request.ProductID),
-			zap.Error(err))
-	} else if !available {
-		return nil, models.ErrInsufficientStock
+		return nil, err
 	}
 
-	// Get or create cart
-	cart, err := s.GetCart(ctx, userID)
+	// Check inventory for every resolved item in one round trip.
+	skuQtys := make([]clients.SKUQty, len(cartItems))
+	for i, item := range cartItems {
+		skuQtys[i] = clients.SKUQty{SKU: item.SKU, Quantity: item.Quantity}
+	}
+	available, err := s.inventoryClient.CheckAvailabilityBatch(ctx, skuQtys)
 	if err != nil {
-		return nil, err
+		s.logger.Warn("Failed to check inventory, allowing operation",
+			zap.String("productID", request.ProductID),
+			zap.Error(err))
+	} else {
+		for _, item := range cartItems {
+			if !available[item.SKU] {
+				return nil, models.ErrInsufficientStock
+			}
+		}
 	}
 
-	// Create cart item
-	cartItem := models.CartItem{
-		ProductID:   productInfo.ID,
-		ProductName: productInfo.Name,
-		SKU:         productInfo.SKU,
-		Price:       productInfo.Price,
-		Quantity:    request.Quantity,
-		ImageURL:    productInfo.ImageURL,
-		Category:    productInfo.Category,
-		AddedAt:     time.Now().UTC(),
+	reservationID := cartItems[0].ReservationID
+
+	// Baggage set by middleware.CartContext (or propagated from an upstream
+	// Dapr caller) lets pricing experiments scope the item limits without
+	// changing this call site's signature.
+	cc := cartctx.FromContext(ctx)
+	maxItems, maxItemQty := s.cartLimits(cc.Experiment)
+	expectedErrs := []error{models.ErrMaxItemsExceeded, models.ErrMaxQuantityExceeded, models.ErrCartExpired}
+
+	// Run the read-cart -> reserve-stock -> persist-cart sequence as a saga:
+	// if persisting the cart fails after stock has already been reserved,
+	// the saga compensates by releasing it, rather than leaking a
+	// reservation no cart item references.
+	var cart *models.Cart
+	var etag string
+	steps := []saga.Step{
+		{
+			Name: "read_cart",
+			Do: func(ctx context.Context) error {
+				c, e, err := s.getOrCreateCartWithETag(ctx, userID)
+				if err != nil {
+					return err
+				}
+				cart, etag = c, e
+				return nil
+			},
+		},
+		{
+			Name: "reserve_stock",
+			Data: reserveStockData{Items: toReserveStockItems(cartItems)},
+			Do: func(ctx context.Context) error {
+				// The inventory round trip is the one step in this saga slow
+				// enough to risk outliving repoToken's original lease, so
+				// refresh it here rather than around every step; a failed
+				// refresh just means a newer holder has already taken over,
+				// which persist_cart's SaveCartWithToken will also reject.
+				if tokenOK {
+					if err := s.repo.RefreshLock(ctx, userID, repoToken, s.config.Lock.LeaseTTL); err != nil {
+						s.logger.Warn("Failed to refresh cart lock token",
+							zap.String("userID", userID), zap.Error(err))
+					}
+				}
+				// The saga framework only compensates steps that already
+				// completed (see pkg/saga.Orchestrator.Run), so a failure
+				// partway through a multi-item (bundle) reservation must
+				// release what it already reserved itself before returning.
+				reserved := make([]models.CartItem, 0, len(cartItems))
+				for _, item := range cartItems {
+					if err := s.inventoryClient.ReserveStock(ctx, item.SKU, item.Quantity); err != nil {
+						for _, r := range reserved {
+							s.inventoryClient.ReleaseStock(ctx, r.SKU, r.Quantity)
+						}
+						return err
+					}
+					reserved = append(reserved, item)
+				}
+				return nil
+			},
+			Compensate: func(ctx context.Context) error {
+				var firstErr error
+				for _, item := range cartItems {
+					if err := s.inventoryClient.ReleaseStock(ctx, item.SKU, item.Quantity); err != nil && firstErr == nil {
+						firstErr = err
+					}
+				}
+				return firstErr
+			},
+		},
+		{
+			Name: "persist_cart",
+			Do: func(ctx context.Context) error {
+				mutate := func(cart *models.Cart) error {
+					return tracing.WithCartSpan(ctx, "add_item", userID, expectedErrs, func(ctx context.Context) error {
+						for _, item := range cartItems {
+							if err := cart.AddItem(item, maxItems, maxItemQty); err != nil {
+								return err
+							}
+						}
+						tracing.SetCartTotalItems(ctx, cart.TotalItems)
+						tracing.SetCartTotalPrice(ctx, cart.TotalPrice)
+						return nil
+					})
+				}
+
+				// When the repository granted a fencing token, trust it over
+				// the ETag-retry loop: it also catches the narrow case where
+				// this holder's Dapr lock lease expired and a newer holder
+				// has already taken over, which a plain ETag match would miss
+				// if nothing else has touched the cart's content yet.
+				if tokenOK {
+					if err := mutate(cart); err != nil {
+						return err
+					}
+					if err := s.repo.SaveCartWithToken(ctx, cart, repoToken); err != nil {
+						return fmt.Errorf("failed to save cart: %w", err)
+					}
+					return nil
+				}
+
+				saved, err := s.saveCartWithRetry(ctx, userID, cart, etag, mutate)
+				if err != nil {
+					return err
+				}
+				cart = saved
+				return nil
+			},
+		},
 	}
 
-	// Add item to cart
-	if err := cart.AddItem(cartItem, s.config.Cart.MaxItems, s.config.Cart.MaxItemQty); err != nil {
+	sagaID := fmt.Sprintf("addItem:%s:%s", userID, reservationID)
+	if err := s.saga.Run(ctx, sagaID, steps); err != nil {
 		return nil, err
 	}
 
-	// Save cart
-	if err := s.repo.SaveCart(ctx, cart); err != nil {
-		return nil, fmt.Errorf("failed to save cart: %w", err)
+	totalQty := 0
+	for _, item := range cartItems {
+		totalQty += item.Quantity
 	}
 
-	s.logger.Info("Item added to cart successfully", 
+	s.logger.Info("Item added to cart successfully",
 		zap.String("userID", userID),
 		zap.String("productID", request.ProductID),
-		zap.Int("quantity", request.Quantity))
+		zap.Int("quantity", totalQty))
+
+	s.metrics.ItemsAdded.Add(ctx, int64(totalQty))
+	s.publish(ctx, models.WebhookEventItemAdded, userID, cart)
+	s.recordOutboxEvent(ctx, models.OutboxEventCartItemAdded, userID, cart)
 
 	return cart, nil
 }
 
+// toReserveStockItems converts the resolved cart items for an AddItem call
+// into the saga.Step.Data shape "reserve_stock" persists.
+func toReserveStockItems(cartItems []models.CartItem) []reserveStockItem {
+	items := make([]reserveStockItem, len(cartItems))
+	for i, item := range cartItems {
+		items[i] = reserveStockItem{SKU: item.SKU, Quantity: item.Quantity}
+	}
+	return items
+}
+
 // UpdateItem updates an item quantity in the cart
 func (s *cartService) UpdateItem(ctx context.Context, userID string, productID string, request models.UpdateItemRequest) (*models.Cart, error) {
 	s.logger.Debug("Updating item in cart", 
@@ -174,18 +725,15 @@ func (s *cartService) UpdateItem(ctx context.Context, userID string, productID s
 		zap.String("productID", productID),
 		zap.Int("quantity", request.Quantity))
 
-	// Acquire lock for cart operations
-	lockAcquired, err := s.repo.AcquireLock(ctx, userID, 30*time.Second)
+	// Acquire the distributed per-user lock for the whole read-modify-write cycle
+	heldLock, err := s.acquireLock(ctx, userID, lockOwnerID(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire cart lock: %w", err)
 	}
-	if !lockAcquired {
-		return nil, fmt.Errorf("cart is currently being modified, please try again")
-	}
-	defer s.repo.ReleaseLock(ctx, userID)
+	defer s.locker.Release(ctx, heldLock)
 
 	// Get cart
-	cart, err := s.repo.GetCart(ctx, userID)
+	cart, etag, err := s.repo.GetCartWithETag(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +745,7 @@ func (s *cartService) UpdateItem(ctx context.Context, userID string, productID s
 			additionalQty := request.Quantity - currentItem.Quantity
 			available, err := s.inventoryClient.CheckAvailability(ctx, productID, additionalQty)
 			if err != nil {
-				s.logger.Warn("Failed to check inventory, allowing operation", 
+				s.logger.Warn("Failed to check inventory, allowing operation",
 					zap.String("productID", productID),
 					zap.Error(err))
 			} else if !available {
@@ -207,20 +755,29 @@ func (s *cartService) UpdateItem(ctx context.Context, userID string, productID s
 	}
 
 	// Update item quantity
-	if err := cart.UpdateItemQuantity(productID, request.Quantity, s.config.Cart.MaxItemQty); err != nil {
+	expectedErrs := []error{models.ErrItemNotFound, models.ErrMaxQuantityExceeded, models.ErrInvalidQuantity, models.ErrCartExpired}
+	cart, err = s.saveCartWithRetry(ctx, userID, cart, etag, func(cart *models.Cart) error {
+		return tracing.WithCartSpan(ctx, "update_item", userID, expectedErrs, func(ctx context.Context) error {
+			if err := cart.UpdateItemQuantity(productID, request.Quantity, s.maxItemQty()); err != nil {
+				return err
+			}
+			tracing.SetCartTotalItems(ctx, cart.TotalItems)
+			tracing.SetCartTotalPrice(ctx, cart.TotalPrice)
+			return nil
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Save cart
-	if err := s.repo.SaveCart(ctx, cart); err != nil {
-		return nil, fmt.Errorf("failed to save cart: %w", err)
-	}
-
-	s.logger.Info("Item updated in cart successfully", 
+	s.logger.Info("Item updated in cart successfully",
 		zap.String("userID", userID),
 		zap.String("productID", productID),
 		zap.Int("quantity", request.Quantity))
 
+	s.publish(ctx, models.WebhookEventItemUpdated, userID, cart)
+	s.recordOutboxEvent(ctx, models.OutboxEventCartItemUpdated, userID, cart)
+
 	return cart, nil
 }
 
@@ -230,36 +787,42 @@ func (s *cartService) RemoveItem(ctx context.Context, userID string, productID s
 		zap.String("userID", userID),
 		zap.String("productID", productID))
 
-	// Acquire lock for cart operations
-	lockAcquired, err := s.repo.AcquireLock(ctx, userID, 30*time.Second)
+	// Acquire the distributed per-user lock for the whole read-modify-write cycle
+	heldLock, err := s.acquireLock(ctx, userID, lockOwnerID(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire cart lock: %w", err)
 	}
-	if !lockAcquired {
-		return nil, fmt.Errorf("cart is currently being modified, please try again")
-	}
-	defer s.repo.ReleaseLock(ctx, userID)
+	defer s.locker.Release(ctx, heldLock)
 
 	// Get cart
-	cart, err := s.repo.GetCart(ctx, userID)
+	cart, etag, err := s.repo.GetCartWithETag(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Remove item
-	if err := cart.RemoveItem(productID); err != nil {
+	expectedErrs := []error{models.ErrItemNotFound, models.ErrCartExpired}
+	cart, err = s.saveCartWithRetry(ctx, userID, cart, etag, func(cart *models.Cart) error {
+		return tracing.WithCartSpan(ctx, "remove_item", userID, expectedErrs, func(ctx context.Context) error {
+			if err := cart.RemoveItem(productID); err != nil {
+				return err
+			}
+			tracing.SetCartTotalItems(ctx, cart.TotalItems)
+			tracing.SetCartTotalPrice(ctx, cart.TotalPrice)
+			return nil
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Save cart
-	if err := s.repo.SaveCart(ctx, cart); err != nil {
-		return nil, fmt.Errorf("failed to save cart: %w", err)
-	}
-
-	s.logger.Info("Item removed from cart successfully", 
+	s.logger.Info("Item removed from cart successfully",
 		zap.String("userID", userID),
 		zap.String("productID", productID))
 
+	s.publish(ctx, models.WebhookEventItemRemoved, userID, cart)
+	s.recordOutboxEvent(ctx, models.OutboxEventCartItemRemoved, userID, cart)
+
 	return cart, nil
 }
 
@@ -267,49 +830,178 @@ func (s *cartService) RemoveItem(ctx context.Context, userID string, productID s
 func (s *cartService) ClearCart(ctx context.Context, userID string) error {
 	s.logger.Debug("Clearing cart", zap.String("userID", userID))
 
-	// Acquire lock for cart operations
-	lockAcquired, err := s.repo.AcquireLock(ctx, userID, 30*time.Second)
+	// Acquire the distributed per-user lock for the operation
+	heldLock, err := s.acquireLock(ctx, userID, lockOwnerID(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to acquire cart lock: %w", err)
 	}
-	if !lockAcquired {
-		return fmt.Errorf("cart is currently being modified, please try again")
-	}
-	defer s.repo.ReleaseLock(ctx, userID)
+	defer s.locker.Release(ctx, heldLock)
 
 	// Delete cart from Redis
-	if err := s.repo.DeleteCart(ctx, userID); err != nil {
+	if err := tracing.WithCartSpan(ctx, "clear", userID, nil, func(ctx context.Context) error {
+		return s.repo.DeleteCart(ctx, userID)
+	}); err != nil {
 		return fmt.Errorf("failed to clear cart: %w", err)
 	}
 
 	s.logger.Info("Cart cleared successfully", zap.String("userID", userID))
+
+	s.publish(ctx, models.WebhookEventCleared, userID, nil)
+
+	// No recordOutboxEvent here: ClearCart deletes the cart rather than
+	// saving one, and SaveCartWithOutbox has nothing to write the event
+	// alongside in the same transaction.
+
 	return nil
 }
 
+// BulkApply applies several add/update/remove operations to a single loaded
+// cart and saves it once, so clients syncing a locally-edited cart (e.g. the
+// guest->user merge flow TransferCart feeds into) can do it in one
+// round-trip instead of N calls. Each op's outcome is reported independently;
+// a failing op does not abort the rest of the batch.
+func (s *cartService) BulkApply(ctx context.Context, ownerID string, ops []models.CartOp) (cart *models.Cart, results []models.OpResult, err error) {
+	ctx, span := tracing.StartCartSpan(ctx, "bulk_apply", ownerID)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	s.logger.Debug("Applying bulk cart operations",
+		zap.String("ownerID", ownerID),
+		zap.Int("opCount", len(ops)))
+
+	heldLock, err := s.acquireLock(ctx, ownerID, lockOwnerID(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire cart lock: %w", err)
+	}
+	defer s.locker.Release(ctx, heldLock)
+
+	cart, err = s.GetCart(ctx, ownerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cc := cartctx.FromContext(ctx)
+	maxItems, maxItemQty := s.cartLimits(cc.Experiment)
+
+	addSKUs := make([]clients.SKUQty, 0, len(ops))
+	for _, op := range ops {
+		if op.Type == models.CartOpAdd {
+			addSKUs = append(addSKUs, clients.SKUQty{SKU: op.ProductID, Quantity: op.Quantity})
+		}
+	}
+	availability := s.checkAvailabilityBatch(ctx, addSKUs)
+
+	results = make([]models.OpResult, 0, len(ops))
+	for _, op := range ops {
+		result := models.OpResult{ProductID: op.ProductID, Success: true}
+
+		switch op.Type {
+		case models.CartOpAdd:
+			if !availability[op.ProductID] {
+				result.Success = false
+				result.Error = models.ErrInsufficientStock.Error()
+			}
+
+			if result.Success {
+				productInfo, err := s.productClient.GetProduct(ctx, op.ProductID)
+				if err != nil {
+					result.Success = false
+					result.Error = fmt.Sprintf("failed to get product information: %v", err)
+				} else {
+					cartItem := models.CartItem{
+						ProductID:   productInfo.ID,
+						ProductName: productInfo.Name,
+						SKU:         productInfo.SKU,
+						Price:       productInfo.Price,
+						Quantity:    op.Quantity,
+						ImageURL:    productInfo.ImageURL,
+						Category:    productInfo.Category,
+						AddedAt:     time.Now().UTC(),
+					}
+					if err := cart.AddItem(cartItem, maxItems, maxItemQty); err != nil {
+						result.Success = false
+						result.Error = err.Error()
+					}
+				}
+			}
+
+		case models.CartOpUpdate:
+			if err := cart.UpdateItemQuantity(op.ProductID, op.Quantity, maxItemQty); err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+
+		case models.CartOpRemove:
+			if err := cart.RemoveItem(op.ProductID); err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+
+		default:
+			result.Success = false
+			result.Error = fmt.Sprintf("unknown op type %q", op.Type)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := s.repo.SaveCart(ctx, cart); err != nil {
+		return nil, results, fmt.Errorf("failed to save cart: %w", err)
+	}
+
+	s.logger.Info("Bulk cart operations applied",
+		zap.String("ownerID", ownerID),
+		zap.Int("opCount", len(ops)))
+
+	s.publish(ctx, models.WebhookEventItemUpdated, ownerID, cart)
+
+	return cart, results, nil
+}
+
 // TransferCart transfers items from one cart to another (guest to user)
-func (s *cartService) TransferCart(ctx context.Context, fromUserID, toUserID string) (*models.Cart, error) {
-	s.logger.Debug("Transferring cart", 
+func (s *cartService) TransferCart(ctx context.Context, fromUserID, toUserID string) (toCart *models.Cart, err error) {
+	ctx, span := tracing.StartCartSpan(ctx, "transfer_cart", toUserID)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	s.logger.Debug("Transferring cart",
 		zap.String("fromUserID", fromUserID),
 		zap.String("toUserID", toUserID))
 
 	// Acquire locks for both carts
-	fromLockAcquired, err := s.repo.AcquireLock(ctx, fromUserID, 30*time.Second)
+	ownerID := lockOwnerID(ctx)
+	fromLock, err := s.acquireLock(ctx, fromUserID, ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire source cart lock: %w", err)
 	}
-	if !fromLockAcquired {
-		return nil, fmt.Errorf("source cart is currently being modified, please try again")
-	}
-	defer s.repo.ReleaseLock(ctx, fromUserID)
+	defer s.locker.Release(ctx, fromLock)
 
-	toLockAcquired, err := s.repo.AcquireLock(ctx, toUserID, 30*time.Second)
+	toLock, err := s.acquireLock(ctx, toUserID, ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire target cart lock: %w", err)
 	}
-	if !toLockAcquired {
-		return nil, fmt.Errorf("target cart is currently being modified, please try again")
+	defer s.locker.Release(ctx, toLock)
+
+	// Also take out repository-level fencing tokens for both carts, the
+	// same Redlock-style guard AddItem uses: if either Dapr lease expires
+	// mid-transfer and another process acquires the lock next, the token is
+	// superseded and the save below is rejected instead of clobbering the
+	// newer holder's write. A transfer can involve an inventory round trip
+	// per item below, which is the slow step worth refreshing the tokens
+	// around.
+	fromToken, fromTokenOK, err := s.repo.AcquireLockWithToken(ctx, fromUserID, s.config.Lock.LeaseTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire source cart lock token: %w", err)
+	}
+	if fromTokenOK {
+		defer s.repo.ReleaseLockWithToken(ctx, fromUserID, fromToken)
+	}
+
+	toToken, toTokenOK, err := s.repo.AcquireLockWithToken(ctx, toUserID, s.config.Lock.LeaseTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire target cart lock token: %w", err)
+	}
+	if toTokenOK {
+		defer s.repo.ReleaseLockWithToken(ctx, toUserID, toToken)
 	}
-	defer s.repo.ReleaseLock(ctx, toUserID)
 
 	// Get source cart
 	fromCart, err := s.repo.GetCart(ctx, fromUserID)
@@ -322,67 +1014,119 @@ func (s *cartService) TransferCart(ctx context.Context, fromUserID, toUserID str
 	}
 
 	// Get or create target cart
-	toCart, err := s.GetCart(ctx, toUserID)
+	toCart, err = s.GetCart(ctx, toUserID)
 	if err != nil {
 		return nil, err
 	}
 
+	// The inventory round trip below is the slowest step in a transfer, so
+	// refresh both fencing tokens around it rather than relying on the
+	// lease taken out above; a failed refresh just means a newer holder has
+	// already taken over, which the token-gated save below also rejects.
+	if fromTokenOK {
+		if err := s.repo.RefreshLock(ctx, fromUserID, fromToken, s.config.Lock.LeaseTTL); err != nil {
+			s.logger.Warn("Failed to refresh source cart lock token", zap.String("fromUserID", fromUserID), zap.Error(err))
+		}
+	}
+	if toTokenOK {
+		if err := s.repo.RefreshLock(ctx, toUserID, toToken, s.config.Lock.LeaseTTL); err != nil {
+			s.logger.Warn("Failed to refresh target cart lock token", zap.String("toUserID", toUserID), zap.Error(err))
+		}
+	}
+
+	// Re-check inventory for the merge in one round-trip rather than per item,
+	// so a large guest cart doesn't turn the transfer into N Dapr calls.
+	skuQtys := make([]clients.SKUQty, 0, len(fromCart.Items))
+	for _, item := range fromCart.Items {
+		skuQtys = append(skuQtys, clients.SKUQty{SKU: item.ProductID, Quantity: item.Quantity})
+	}
+	availability := s.checkAvailabilityBatch(ctx, skuQtys)
+
 	// Transfer items
 	for _, item := range fromCart.Items {
-		if err := toCart.AddItem(item, s.config.Cart.MaxItems, s.config.Cart.MaxItemQty); err != nil {
-			s.logger.Warn("Failed to transfer item, skipping", 
+		if !availability[item.ProductID] {
+			s.logger.Warn("Skipping out-of-stock item during cart transfer",
+				zap.String("productID", item.ProductID))
+			continue
+		}
+		if err := toCart.AddItem(item, s.maxItems(), s.maxItemQty()); err != nil {
+			s.logger.Warn("Failed to transfer item, skipping",
 				zap.String("productID", item.ProductID),
 				zap.Error(err))
 			continue
 		}
 	}
 
-	// Save target cart
-	if err := s.repo.SaveCart(ctx, toCart); err != nil {
+	// Save target cart. When the repository granted a fencing token, trust
+	// it over a plain save: it also catches the case where this holder's
+	// Dapr lock lease expired and a newer holder has already taken over.
+	if toTokenOK {
+		if err := s.repo.SaveCartWithToken(ctx, toCart, toToken); err != nil {
+			return nil, fmt.Errorf("failed to save target cart: %w", err)
+		}
+	} else if err := s.repo.SaveCart(ctx, toCart); err != nil {
 		return nil, fmt.Errorf("failed to save target cart: %w", err)
 	}
 
-	// Delete source cart
-	if err := s.repo.DeleteCart(ctx, fromUserID); err != nil {
-		s.logger.Error("Failed to delete source cart after transfer", 
+	// Delete source cart. When the repository granted a fencing token,
+	// trust it over a plain delete for the same reason the target cart's
+	// save does: it also catches the case where this holder's Dapr lock
+	// lease expired and a newer holder has already taken over the source
+	// cart.
+	var deleteErr error
+	if fromTokenOK {
+		deleteErr = s.repo.DeleteCartWithToken(ctx, fromUserID, fromToken)
+	} else {
+		deleteErr = s.repo.DeleteCart(ctx, fromUserID)
+	}
+	if deleteErr != nil {
+		s.logger.Error("Failed to delete source cart after transfer",
 			zap.String("fromUserID", fromUserID),
-			zap.Error(err))
+			zap.Error(deleteErr))
 	}
 
-	s.logger.Info("Cart transferred successfully", 
+	s.logger.Info("Cart transferred successfully",
 		zap.String("fromUserID", fromUserID),
 		zap.String("toUserID", toUserID),
 		zap.Int("itemsTransferred", len(fromCart.Items)))
 
+	s.publish(ctx, models.WebhookEventTransferred, toUserID, toCart)
+	s.recordOutboxEvent(ctx, models.OutboxEventCartTransferred, toUserID, toCart)
+
 	return toCart, nil
 }
 
 // ValidateCart validates all items in the cart against current product and inventory data
-func (s *cartService) ValidateCart(ctx context.Context, userID string) (*models.Cart, error) {
+func (s *cartService) ValidateCart(ctx context.Context, userID string) (cart *models.Cart, err error) {
+	ctx, span := tracing.StartCartSpan(ctx, "validate_cart", userID)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	s.logger.Debug("Validating cart", zap.String("userID", userID))
 
-	cart, err := s.repo.GetCart(ctx, userID)
+	cart, err = s.repo.GetCart(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	hasChanges := false
-	validItems := make([]models.CartItem, 0, len(cart.Items))
+	removedCount := 0
+	candidates := make([]models.CartItem, 0, len(cart.Items))
 
 	for _, item := range cart.Items {
 		// Check product availability
 		productInfo, err := s.productClient.GetProduct(ctx, item.ProductID)
 		if err != nil || !productInfo.IsActive {
-			s.logger.Info("Removing unavailable product from cart", 
+			s.logger.Info("Removing unavailable product from cart",
 				zap.String("userID", userID),
 				zap.String("productID", item.ProductID))
 			hasChanges = true
+			removedCount++
 			continue
 		}
 
 		// Update price if changed
 		if item.Price != productInfo.Price {
-			s.logger.Info("Updating product price in cart", 
+			s.logger.Info("Updating product price in cart",
 				zap.String("userID", userID),
 				zap.String("productID", item.ProductID),
 				zap.Float64("oldPrice", item.Price),
@@ -392,34 +1136,46 @@ func (s *cartService) ValidateCart(ctx context.Context, userID string) (*models.
 			hasChanges = true
 		}
 
-		// Check inventory availability
-		available, err := s.inventoryClient.CheckAvailability(ctx, item.ProductID, item.Quantity)
-		if err != nil {
-			s.logger.Warn("Failed to check inventory during validation", 
+		candidates = append(candidates, item)
+	}
+
+	// Fetch available quantities for all surviving items in one round-trip
+	// (falling back to per-SKU calls against older inventory deployments)
+	// instead of a CheckAvailability+GetAvailableQuantity pair per item.
+	skus := make([]string, 0, len(candidates))
+	for _, item := range candidates {
+		skus = append(skus, item.ProductID)
+	}
+	availableQtys := s.availableQuantitiesBatch(ctx, skus)
+
+	validItems := make([]models.CartItem, 0, len(candidates))
+	for _, item := range candidates {
+		availableQty, known := availableQtys[item.ProductID]
+		if !known {
+			// Inventory lookup failed for this SKU; keep the item as-is
+			// rather than guessing at its stock.
+			validItems = append(validItems, item)
+			continue
+		}
+
+		if availableQty <= 0 {
+			s.logger.Info("Removing out-of-stock product from cart",
+				zap.String("userID", userID),
+				zap.String("productID", item.ProductID))
+			hasChanges = true
+			removedCount++
+			continue
+		}
+
+		if availableQty < item.Quantity {
+			s.logger.Info("Adjusting quantity to available stock",
+				zap.String("userID", userID),
 				zap.String("productID", item.ProductID),
-				zap.Error(err))
-		} else if !available {
-			// Get available quantity
-			availableQty, err := s.inventoryClient.GetAvailableQuantity(ctx, item.ProductID)
-			if err != nil || availableQty <= 0 {
-				s.logger.Info("Removing out-of-stock product from cart", 
-					zap.String("userID", userID),
-					zap.String("productID", item.ProductID))
-				hasChanges = true
-				continue
-			}
-			
-			// Adjust quantity to available amount
-			if availableQty < item.Quantity {
-				s.logger.Info("Adjusting quantity to available stock", 
-					zap.String("userID", userID),
-					zap.String("productID", item.ProductID),
-					zap.Int("requestedQty", item.Quantity),
-					zap.Int("availableQty", availableQty))
-				item.Quantity = availableQty
-				item.Subtotal = float64(item.Quantity) * item.Price
-				hasChanges = true
-			}
+				zap.Int("requestedQty", item.Quantity),
+				zap.Int("availableQty", availableQty))
+			item.Quantity = availableQty
+			item.Subtotal = float64(item.Quantity) * item.Price
+			hasChanges = true
 		}
 
 		validItems = append(validItems, item)
@@ -428,22 +1184,29 @@ func (s *cartService) ValidateCart(ctx context.Context, userID string) (*models.
 	if hasChanges {
 		cart.Items = validItems
 		cart.UpdateTotals()
-		
-		if err := s.repo.SaveCart(ctx, cart); err != nil {
+
+		if err = s.repo.SaveCart(ctx, cart); err != nil {
 			return nil, fmt.Errorf("failed to save validated cart: %w", err)
 		}
 
-		s.logger.Info("Cart validated and updated", 
+		s.logger.Info("Cart validated and updated",
 			zap.String("userID", userID),
 			zap.Int("originalItems", len(cart.Items)),
 			zap.Int("validItems", len(validItems)))
+
+		if removedCount > 0 {
+			s.metrics.ValidationRemovedItems.Add(ctx, int64(removedCount))
+		}
 	}
 
 	return cart, nil
 }
 
 // GetCartSummary returns a summary of the cart for order processing
-func (s *cartService) GetCartSummary(ctx context.Context, userID string) (*models.CartSummary, error) {
+func (s *cartService) GetCartSummary(ctx context.Context, userID string) (summary *models.CartSummary, err error) {
+	ctx, span := tracing.StartCartSpan(ctx, "get_cart_summary", userID)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	cart, err := s.ValidateCart(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/webhooks"
+	"go.uber.org/zap"
+)
+
+// CachedStore composes a durable primary store (e.g. Postgres) with a fast
+// cache (e.g. Redis) in front of it, using a write-through strategy: every
+// Save/Delete goes to both, while Get prefers the cache and falls back to
+// the primary, repopulating the cache on a miss.
+type CachedStore struct {
+	primary Store
+	cache   Store
+	logger  *zap.Logger
+}
+
+// NewCachedStore builds a write-through CachedStore.
+func NewCachedStore(primary, cache Store, logger *zap.Logger) *CachedStore {
+	return &CachedStore{primary: primary, cache: cache, logger: logger}
+}
+
+func (s *CachedStore) Get(ctx context.Context, userID string) (*models.Cart, error) {
+	cart, err := s.cache.Get(ctx, userID)
+	if err == nil {
+		return cart, nil
+	}
+	if err != models.ErrCartNotFound {
+		s.logger.Warn("Cache read failed, falling back to primary store", zap.String("userID", userID), zap.Error(err))
+	}
+
+	cart, err = s.primary.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheErr := s.cache.Save(ctx, cart); cacheErr != nil {
+		s.logger.Warn("Failed to repopulate cache after primary read", zap.String("userID", userID), zap.Error(cacheErr))
+	}
+
+	return cart, nil
+}
+
+func (s *CachedStore) Save(ctx context.Context, cart *models.Cart) error {
+	if err := s.primary.Save(ctx, cart); err != nil {
+		return err
+	}
+	if err := s.cache.Save(ctx, cart); err != nil {
+		s.logger.Warn("Failed to write cart to cache after primary save", zap.String("userID", cart.UserID), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *CachedStore) Delete(ctx context.Context, userID string) error {
+	if err := s.primary.Delete(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.cache.Delete(ctx, userID); err != nil {
+		s.logger.Warn("Failed to delete cart from cache after primary delete", zap.String("userID", userID), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *CachedStore) ListExpired(ctx context.Context, limit int) ([]*models.Cart, error) {
+	return s.primary.ListExpired(ctx, limit)
+}
+
+// Reaper periodically deletes carts past their expiry from the primary
+// store (and, transitively, the cache), so Cart.IsExpired() semantics hold
+// across restarts even when the cache's own TTL has not yet evicted a key.
+type Reaper struct {
+	store     *CachedStore
+	interval  time.Duration
+	batch     int
+	logger    *zap.Logger
+	stop      chan struct{}
+	publisher webhooks.Publisher
+}
+
+// NewReaper creates a background reaper over the given CachedStore. publisher
+// may be nil, in which case expired carts are purged without emitting a
+// cart.abandoned webhook event.
+func NewReaper(store *CachedStore, interval time.Duration, batch int, logger *zap.Logger, publisher webhooks.Publisher) *Reaper {
+	if interval <= 0 {
+		interval = DefaultReaperInterval
+	}
+	if batch <= 0 {
+		batch = 100
+	}
+	return &Reaper{store: store, interval: interval, batch: batch, logger: logger, stop: make(chan struct{}), publisher: publisher}
+}
+
+// Run blocks, sweeping on Reaper's interval until ctx is done or Stop is called.
+func (r *Reaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.Sweep(ctx)
+		}
+	}
+}
+
+// Stop ends a running Reaper loop.
+func (r *Reaper) Stop() {
+	close(r.stop)
+}
+
+// Sweep runs a single reap pass immediately; Run calls this on its ticker,
+// but tests and admin tooling can invoke it directly.
+func (r *Reaper) Sweep(ctx context.Context) {
+	expired, err := r.store.ListExpired(ctx, r.batch)
+	if err != nil {
+		r.logger.Error("Reaper failed to list expired carts", zap.Error(err))
+		return
+	}
+
+	for _, cart := range expired {
+		if err := r.store.Delete(ctx, cart.UserID); err != nil {
+			r.logger.Error("Reaper failed to delete expired cart", zap.String("userID", cart.UserID), zap.Error(err))
+			continue
+		}
+		if r.publisher != nil {
+			r.publisher.Publish(ctx, models.WebhookEvent{
+				Type:       models.WebhookEventAbandoned,
+				UserID:     cart.UserID,
+				Cart:       cart,
+				OccurredAt: time.Now().UTC(),
+			})
+		}
+	}
+
+	if len(expired) > 0 {
+		r.logger.Info("Reaper purged expired carts", zap.Int("count", len(expired)))
+	}
+}
@@ -0,0 +1,144 @@
+// Package postgres implements storage.Store on top of PostgreSQL via pgx,
+// so carts survive a Redis restart or eviction.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Schema is the DDL this store expects to already be applied (via migration
+// tooling, not at runtime):
+//
+//	CREATE TABLE carts (
+//	    user_id    TEXT PRIMARY KEY,
+//	    data       JSONB NOT NULL,
+//	    expires_at TIMESTAMPTZ NOT NULL,
+//	    version    INT NOT NULL DEFAULT 1
+//	);
+const Schema = `
+CREATE TABLE IF NOT EXISTS carts (
+    user_id    TEXT PRIMARY KEY,
+    data       JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    version    INT NOT NULL DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS carts_expires_at_idx ON carts (expires_at);
+`
+
+// Store implements storage.Store backed by a `carts` table whose `version`
+// column counts writes. Save does not compare it, so concurrent writers are
+// not detected and the last write wins.
+type Store struct {
+	pool   *pgxpool.Pool
+	logger *zap.Logger
+}
+
+// NewStore creates a new Postgres-backed store using the given DSN.
+func NewStore(ctx context.Context, dsn string, logger *zap.Logger) (*Store, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	return &Store{pool: pool, logger: logger}, nil
+}
+
+// Close releases the underlying connection pool.
+func (s *Store) Close() {
+	s.pool.Close()
+}
+
+// Get retrieves a cart by user ID.
+func (s *Store) Get(ctx context.Context, userID string) (*models.Cart, error) {
+	var data []byte
+	var expiresAt time.Time
+
+	row := s.pool.QueryRow(ctx, `SELECT data, expires_at FROM carts WHERE user_id = $1`, userID)
+	if err := row.Scan(&data, &expiresAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrCartNotFound
+		}
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	var cart models.Cart
+	if err := json.Unmarshal(data, &cart); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cart: %w", err)
+	}
+
+	return &cart, nil
+}
+
+// Save upserts a cart, bumping its version on every write. It does not
+// compare the stored version with one the caller read: the last write always
+// wins, and it never returns storage.ErrVersionConflict.
+func (s *Store) Save(ctx context.Context, cart *models.Cart) error {
+	data, err := json.Marshal(cart)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cart: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO carts (user_id, data, expires_at, version)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (user_id)
+		DO UPDATE SET data = EXCLUDED.data, expires_at = EXCLUDED.expires_at, version = carts.version + 1
+	`, cart.UserID, data, cart.ExpiresAt)
+	if err != nil {
+		s.logger.Error("Failed to save cart to postgres", zap.String("userID", cart.UserID), zap.Error(err))
+		return fmt.Errorf("failed to save cart: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a cart row.
+func (s *Store) Delete(ctx context.Context, userID string) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM carts WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	return nil
+}
+
+// ListExpired returns up to limit carts whose expires_at is in the past, for
+// use by the storage.Store reaper.
+func (s *Store) ListExpired(ctx context.Context, limit int) ([]*models.Cart, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT data FROM carts WHERE expires_at < now() ORDER BY expires_at ASC LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired carts: %w", err)
+	}
+	defer rows.Close()
+
+	var carts []*models.Cart
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan expired cart: %w", err)
+		}
+		var cart models.Cart
+		if err := json.Unmarshal(data, &cart); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal expired cart: %w", err)
+		}
+		carts = append(carts, &cart)
+	}
+
+	return carts, rows.Err()
+}
+
+var _ storage.Store = (*Store)(nil)
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/aioutlet/cart-service/internal/models"
+)
+
+// redisStore adapts a CartRepository-shaped cart store to the Store
+// interface so it can be used as the cache layer of a CachedStore.
+type redisStore struct {
+	repo cartGetterSaver
+}
+
+// cartGetterSaver is satisfied by repository.CartRepository; declared
+// locally to avoid an import cycle between internal/storage and
+// internal/repository.
+type cartGetterSaver interface {
+	GetCart(ctx context.Context, userID string) (*models.Cart, error)
+	SaveCart(ctx context.Context, cart *models.Cart) error
+	DeleteCart(ctx context.Context, userID string) error
+}
+
+// NewRedisStore wraps a CartRepository-shaped cart store (cmd/server passes
+// its Dapr cart repository) as a Store, so it can serve as the cache tier in
+// a CachedStore.
+func NewRedisStore(repo cartGetterSaver) Store {
+	return &redisStore{repo: repo}
+}
+
+func (s *redisStore) Get(ctx context.Context, userID string) (*models.Cart, error) {
+	return s.repo.GetCart(ctx, userID)
+}
+
+func (s *redisStore) Save(ctx context.Context, cart *models.Cart) error {
+	return s.repo.SaveCart(ctx, cart)
+}
+
+func (s *redisStore) Delete(ctx context.Context, userID string) error {
+	return s.repo.DeleteCart(ctx, userID)
+}
+
+// ListExpired is not supported by the Redis cache tier; TTL-based
+// expiration already evicts stale keys, so the reaper only needs to run
+// against the durable primary store.
+func (s *redisStore) ListExpired(ctx context.Context, limit int) ([]*models.Cart, error) {
+	return nil, nil
+}
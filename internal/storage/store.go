@@ -0,0 +1,37 @@
+// Package storage defines the persistence abstraction cart-service sits on
+// top of, so the Redis-only cache can be backed by a durable store without
+// changing callers in repository or services.
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+)
+
+// Store is the durable persistence contract a CartRepository implementation
+// can compose with a cache in front of it.
+type Store interface {
+	Get(ctx context.Context, userID string) (*models.Cart, error)
+	Save(ctx context.Context, cart *models.Cart) error
+	Delete(ctx context.Context, userID string) error
+	// ListExpired returns up to limit carts whose ExpiresAt has passed,
+	// for use by background reapers.
+	ListExpired(ctx context.Context, limit int) ([]*models.Cart, error)
+}
+
+// ErrVersionConflict reports that a stored row's version did not match the
+// version the caller last read. No Store in this module returns it:
+// postgres.Store.Save does not compare versions.
+type ErrVersionConflict struct {
+	UserID string
+}
+
+func (e *ErrVersionConflict) Error() string {
+	return "storage: version conflict saving cart for user " + e.UserID
+}
+
+// DefaultReaperInterval is how often the background reaper scans for expired
+// carts when no explicit interval is configured.
+const DefaultReaperInterval = 5 * time.Minute
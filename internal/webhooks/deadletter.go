@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const deadLetterKeyPrefix = "webhook_dead_letter:"
+
+// redisDeadLetterStore persists permanently-failed webhook deliveries to a
+// capped Redis list per subscription, so operators can inspect and replay them.
+type redisDeadLetterStore struct {
+	client     *redis.Client
+	logger     *zap.Logger
+	maxEntries int64
+}
+
+// NewRedisDeadLetterStore creates a new Redis-backed dead-letter store.
+func NewRedisDeadLetterStore(client *redis.Client, logger *zap.Logger) DeadLetterStore {
+	return &redisDeadLetterStore{
+		client:     client,
+		logger:     logger,
+		maxEntries: 1000,
+	}
+}
+
+// Record appends entry to its subscription's dead-letter list.
+func (s *redisDeadLetterStore) Record(ctx context.Context, entry DeadLetterEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead-letter entry: %w", err)
+	}
+
+	key := deadLetterKeyPrefix + entry.SubscriptionID
+	if err := s.client.LPush(ctx, key, data).Err(); err != nil {
+		return fmt.Errorf("failed to record dead-letter entry: %w", err)
+	}
+	if err := s.client.LTrim(ctx, key, 0, s.maxEntries-1).Err(); err != nil {
+		s.logger.Warn("Failed to trim dead-letter list",
+			zap.String("subscriptionID", entry.SubscriptionID),
+			zap.Error(err))
+	}
+
+	return nil
+}
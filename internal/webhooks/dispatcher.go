@@ -0,0 +1,189 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	dispatchQueueSize   = 256
+	maxDeliveryAttempts = 5
+	baseRetryDelay      = 2 * time.Second
+	// dispatchWorkerCount is how many goroutines pull concurrently from the
+	// shared delivery queue. A single worker lets one slow or down
+	// subscriber's retry backoff (up to ~30s, see maxDeliveryAttempts) stall
+	// every other subscriber's deliveries behind it; spreading deliveries
+	// across workers bounds that stall to at most one in-flight delivery
+	// per worker.
+	dispatchWorkerCount = 8
+)
+
+// Publisher emits cart lifecycle events to registered webhook subscribers.
+type Publisher interface {
+	Publish(ctx context.Context, event models.WebhookEvent)
+}
+
+// DeadLetterStore records webhook deliveries that exhausted their retries.
+type DeadLetterStore interface {
+	Record(ctx context.Context, entry DeadLetterEntry) error
+}
+
+// DeadLetterEntry describes one permanently-failed webhook delivery.
+type DeadLetterEntry struct {
+	SubscriptionID string              `json:"subscriptionId"`
+	URL            string              `json:"url"`
+	Event          models.WebhookEvent `json:"event"`
+	LastError      string              `json:"lastError"`
+	Attempts       int                 `json:"attempts"`
+	FailedAt       time.Time           `json:"failedAt"`
+}
+
+// delivery is one queued (subscription, event) pair awaiting dispatch.
+type delivery struct {
+	sub           *models.WebhookSubscription
+	event         models.WebhookEvent
+	correlationID string
+}
+
+// Dispatcher is a background worker that delivers webhook events to
+// subscribers with HMAC-signed payloads, retrying transient failures with
+// exponential backoff before giving up to the dead-letter store.
+type Dispatcher struct {
+	store      SubscriptionStore
+	deadLetter DeadLetterStore
+	httpClient *http.Client
+	logger     *zap.Logger
+	queue      chan delivery
+}
+
+// NewDispatcher creates a Dispatcher and starts its pool of background
+// workers. Cancelling ctx stops them.
+func NewDispatcher(ctx context.Context, store SubscriptionStore, deadLetter DeadLetterStore, logger *zap.Logger) *Dispatcher {
+	d := &Dispatcher{
+		store:      store,
+		deadLetter: deadLetter,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     logger,
+		queue:      make(chan delivery, dispatchQueueSize),
+	}
+	for i := 0; i < dispatchWorkerCount; i++ {
+		go d.run(ctx)
+	}
+	return d
+}
+
+// Publish looks up event.UserID's subscriptions and enqueues a delivery for
+// each one subscribed to event.Type. Enqueueing is fire-and-forget: a full
+// queue drops the event rather than blocking the caller's request path.
+func (d *Dispatcher) Publish(ctx context.Context, event models.WebhookEvent) {
+	subs, err := d.store.List(ctx, event.UserID)
+	if err != nil {
+		d.logger.Warn("Failed to list webhook subscriptions for publish",
+			zap.String("userID", event.UserID),
+			zap.String("eventType", string(event.Type)),
+			zap.Error(err))
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.Subscribes(event.Type) {
+			continue
+		}
+		select {
+		case d.queue <- delivery{sub: sub, event: event, correlationID: event.CorrelationID}:
+		default:
+			d.logger.Warn("Webhook dispatch queue full, dropping event",
+				zap.String("subscriptionID", sub.ID),
+				zap.String("eventType", string(event.Type)))
+		}
+	}
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case del := <-d.queue:
+			d.deliver(ctx, del)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, del delivery) {
+	body, err := json.Marshal(del.event)
+	if err != nil {
+		d.logger.Error("Failed to marshal webhook event", zap.Error(err))
+		return
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(backoff(attempt))
+		}
+
+		if lastErr = d.send(ctx, del.sub, body, del.correlationID); lastErr == nil {
+			return
+		}
+
+		d.logger.Warn("Webhook delivery attempt failed",
+			zap.String("subscriptionID", del.sub.ID),
+			zap.Int("attempt", attempt),
+			zap.Error(lastErr))
+	}
+
+	if d.deadLetter == nil {
+		return
+	}
+	entry := DeadLetterEntry{
+		SubscriptionID: del.sub.ID,
+		URL:            del.sub.URL,
+		Event:          del.event,
+		LastError:      lastErr.Error(),
+		Attempts:       maxDeliveryAttempts,
+		FailedAt:       time.Now().UTC(),
+	}
+	if err := d.deadLetter.Record(ctx, entry); err != nil {
+		d.logger.Error("Failed to record dead-lettered webhook delivery",
+			zap.String("subscriptionID", del.sub.ID),
+			zap.Error(err))
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *models.WebhookSubscription, body []byte, correlationID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Signature", Sign(sub.Secret, body))
+	if correlationID != "" {
+		req.Header.Set("X-Correlation-ID", correlationID)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook subscriber returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// backoff returns the delay before retry attempt n (1-indexed), doubling
+// from baseRetryDelay.
+func backoff(attempt int) time.Duration {
+	return baseRetryDelay * time.Duration(math.Pow(2, float64(attempt-2)))
+}
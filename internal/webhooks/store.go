@@ -0,0 +1,180 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	subscriptionKeyPrefix   = "webhook_sub:"
+	subscriptionIndexPrefix = "webhook_subs:"
+)
+
+// SubscriptionStore persists per-owner webhook subscriptions.
+type SubscriptionStore interface {
+	Create(ctx context.Context, ownerID string, req models.CreateWebhookSubscriptionRequest) (*models.WebhookSubscription, error)
+	Get(ctx context.Context, ownerID, id string) (*models.WebhookSubscription, error)
+	List(ctx context.Context, ownerID string) ([]*models.WebhookSubscription, error)
+	Update(ctx context.Context, ownerID, id string, req models.UpdateWebhookSubscriptionRequest) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, ownerID, id string) error
+}
+
+// redisSubscriptionStore implements SubscriptionStore over Redis. Each
+// subscription is a JSON blob keyed by owner+id, with the owner's
+// subscription ids also tracked in a set so List doesn't need a key scan.
+type redisSubscriptionStore struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewRedisSubscriptionStore creates a new Redis-backed subscription store.
+func NewRedisSubscriptionStore(client *redis.Client, logger *zap.Logger) SubscriptionStore {
+	return &redisSubscriptionStore{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Create registers a new subscription for ownerID.
+func (s *redisSubscriptionStore) Create(ctx context.Context, ownerID string, req models.CreateWebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	sub := &models.WebhookSubscription{
+		ID:        uuid.New().String(),
+		OwnerID:   ownerID,
+		URL:       req.URL,
+		Secret:    req.Secret,
+		Events:    req.Events,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.save(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	if err := s.client.SAdd(ctx, s.indexKey(ownerID), sub.ID).Err(); err != nil {
+		s.logger.Error("Failed to index webhook subscription",
+			zap.String("ownerID", ownerID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to index webhook subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// Get retrieves a single subscription owned by ownerID.
+func (s *redisSubscriptionStore) Get(ctx context.Context, ownerID, id string) (*models.WebhookSubscription, error) {
+	data, err := s.client.Get(ctx, s.key(ownerID, id)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, models.ErrWebhookSubscriptionNotFound
+		}
+		s.logger.Error("Failed to get webhook subscription",
+			zap.String("ownerID", ownerID),
+			zap.String("id", id),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	var sub models.WebhookSubscription
+	if err := json.Unmarshal([]byte(data), &sub); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook subscription: %w", err)
+	}
+
+	return &sub, nil
+}
+
+// List returns all subscriptions registered by ownerID.
+func (s *redisSubscriptionStore) List(ctx context.Context, ownerID string) ([]*models.WebhookSubscription, error) {
+	ids, err := s.client.SMembers(ctx, s.indexKey(ownerID)).Result()
+	if err != nil {
+		s.logger.Error("Failed to list webhook subscriptions",
+			zap.String("ownerID", ownerID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	subs := make([]*models.WebhookSubscription, 0, len(ids))
+	for _, id := range ids {
+		sub, err := s.Get(ctx, ownerID, id)
+		if err != nil {
+			if err == models.ErrWebhookSubscriptionNotFound {
+				// Stale index entry left behind by an expired/deleted key; drop it.
+				s.client.SRem(ctx, s.indexKey(ownerID), id)
+				continue
+			}
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, nil
+}
+
+// Update replaces the URL/secret/event filter of an existing subscription.
+func (s *redisSubscriptionStore) Update(ctx context.Context, ownerID, id string, req models.UpdateWebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	sub, err := s.Get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.URL = req.URL
+	sub.Secret = req.Secret
+	sub.Events = req.Events
+
+	if err := s.save(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// Delete removes a subscription.
+func (s *redisSubscriptionStore) Delete(ctx context.Context, ownerID, id string) error {
+	if err := s.client.Del(ctx, s.key(ownerID, id)).Err(); err != nil {
+		s.logger.Error("Failed to delete webhook subscription",
+			zap.String("ownerID", ownerID),
+			zap.String("id", id),
+			zap.Error(err))
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	if err := s.client.SRem(ctx, s.indexKey(ownerID), id).Err(); err != nil {
+		s.logger.Error("Failed to remove webhook subscription from index",
+			zap.String("ownerID", ownerID),
+			zap.String("id", id),
+			zap.Error(err))
+	}
+
+	return nil
+}
+
+func (s *redisSubscriptionStore) save(ctx context.Context, sub *models.WebhookSubscription) error {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook subscription: %w", err)
+	}
+
+	if err := s.client.Set(ctx, s.key(sub.OwnerID, sub.ID), data, 0).Err(); err != nil {
+		s.logger.Error("Failed to save webhook subscription",
+			zap.String("ownerID", sub.OwnerID),
+			zap.String("id", sub.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (s *redisSubscriptionStore) key(ownerID, id string) string {
+	return subscriptionKeyPrefix + ownerID + ":" + id
+}
+
+func (s *redisSubscriptionStore) indexKey(ownerID string) string {
+	return subscriptionIndexPrefix + ownerID
+}
@@ -0,0 +1,298 @@
+package clients
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// CachedProductClientMetrics is a point-in-time snapshot of a
+// CachedProductClient's cumulative cache activity, exposed by
+// CachedProductClient.Metrics for the /metrics endpoint.
+type CachedProductClientMetrics struct {
+	Hits      int `json:"hits"`
+	Misses    int `json:"misses"`
+	Coalesced int `json:"coalesced"`
+}
+
+// cacheEntry is one cached product, tracked in cachedProductClient.order so
+// the least-recently-used entry can be evicted once the cache hits maxSize.
+type cacheEntry struct {
+	productID string
+	product   models.ProductInfo
+	expiresAt time.Time
+	elem      *list.Element
+}
+
+// cachedProductClient wraps a ProductClient with an in-process TTL+LRU
+// cache, coalescing concurrent cache misses for different product IDs into
+// a single GetProducts batch call instead of one GetProduct call per miss.
+// A GetProducts call for several IDs, and many requests missing the cache
+// for the same hot IDs at nearly the same time, both turn into one shared
+// round trip; sequential GetProduct calls from one caller each wait out
+// their own window.
+type cachedProductClient struct {
+	inner       ProductClient
+	ttl         time.Duration
+	maxSize     int
+	batchWindow time.Duration
+	logger      *zap.Logger
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	order   *list.List // front = most recently used
+
+	sf singleflight.Group
+
+	windowMu sync.Mutex
+	window   *batchWindow
+
+	metricsMu sync.Mutex
+	metrics   CachedProductClientMetrics
+}
+
+// batchWindow accumulates product IDs requested while it's open; the first
+// caller to open one schedules a single GetProducts call after batchWindow
+// elapses, and every caller who joined it waits on done for the shared
+// result instead of issuing its own request.
+type batchWindow struct {
+	ids  map[string]struct{}
+	done chan struct{}
+	res  map[string]models.ProductInfo
+	err  error
+}
+
+// NewCachedProductClient wraps inner with a TTL+LRU cache of at most
+// maxSize entries and a batchWindow-wide coalescing window for misses. A
+// non-positive ttl or maxSize falls back to a cache that holds nothing, so
+// misconfiguration degrades to always-miss rather than panicking.
+func NewCachedProductClient(inner ProductClient, ttl time.Duration, maxSize int, batchWindow time.Duration, logger *zap.Logger) ProductClient {
+	return &cachedProductClient{
+		inner:       inner,
+		ttl:         ttl,
+		maxSize:     maxSize,
+		batchWindow: batchWindow,
+		logger:      logger,
+		entries:     make(map[string]*cacheEntry),
+		order:       list.New(),
+	}
+}
+
+// GetProduct returns the cached product for productID if it's present and
+// unexpired, otherwise joins the current batch window (opening one if none
+// is open) and waits for it to resolve.
+func (c *cachedProductClient) GetProduct(ctx context.Context, productID string) (*models.ProductInfo, error) {
+	if product, ok := c.get(productID); ok {
+		return &product, nil
+	}
+
+	results, err := c.batchFetch(ctx, []string{productID})
+	if err != nil {
+		return nil, err
+	}
+	product, ok := results[productID]
+	if !ok {
+		return nil, models.ErrProductNotFound
+	}
+	return &product, nil
+}
+
+// GetProducts returns the cached products it can for productIDs and joins a
+// batch window for the rest, then merges both into a single result slice in
+// the order productIDs was given.
+func (c *cachedProductClient) GetProducts(ctx context.Context, productIDs []string) ([]models.ProductInfo, error) {
+	found := make(map[string]models.ProductInfo, len(productIDs))
+	var missing []string
+	for _, id := range productIDs {
+		if product, ok := c.get(id); ok {
+			found[id] = product
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		fetched, err := c.batchFetch(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for id, product := range fetched {
+			found[id] = product
+		}
+	}
+
+	results := make([]models.ProductInfo, 0, len(productIDs))
+	for _, id := range productIDs {
+		if product, ok := found[id]; ok {
+			results = append(results, product)
+		}
+	}
+	return results, nil
+}
+
+// get returns a cached, unexpired product and records a hit, or records a
+// miss and returns false.
+func (c *cachedProductClient) get(productID string) (models.ProductInfo, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[productID]
+	if ok && time.Now().Before(entry.expiresAt) {
+		c.order.MoveToFront(entry.elem)
+		product := entry.product
+		c.mu.Unlock()
+		c.recordHit()
+		return product, true
+	}
+	c.mu.Unlock()
+	c.recordMiss()
+	return models.ProductInfo{}, false
+}
+
+// batchFetch joins the open batch window for ids (opening one if none is
+// open), waits for it to resolve, and caches every product it returns.
+func (c *cachedProductClient) batchFetch(ctx context.Context, ids []string) (map[string]models.ProductInfo, error) {
+	w, joined := c.joinWindow(ids)
+	if joined {
+		c.recordCoalesced()
+	}
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	for id, product := range w.res {
+		c.put(id, product)
+	}
+	return w.res, nil
+}
+
+// joinWindow adds ids to the currently open batchWindow, opening a new one
+// (and scheduling its fetch) if none is open. joined reports whether the
+// caller piggybacked on a window another caller already opened.
+func (c *cachedProductClient) joinWindow(ids []string) (w *batchWindow, joined bool) {
+	c.windowMu.Lock()
+	defer c.windowMu.Unlock()
+
+	if c.window != nil {
+		for _, id := range ids {
+			c.window.ids[id] = struct{}{}
+		}
+		return c.window, true
+	}
+
+	w = &batchWindow{ids: make(map[string]struct{}, len(ids)), done: make(chan struct{})}
+	for _, id := range ids {
+		w.ids[id] = struct{}{}
+	}
+	c.window = w
+
+	go func() {
+		time.Sleep(c.batchWindow)
+
+		c.windowMu.Lock()
+		c.window = nil
+		c.windowMu.Unlock()
+
+		idList := make([]string, 0, len(w.ids))
+		for id := range w.ids {
+			idList = append(idList, id)
+		}
+
+		// singleflight is a belt-and-braces guard, not the primary
+		// coalescing mechanism: two windows in a row could in principle
+		// race to fetch the same still-missing ID if a caller joins just
+		// after this window closed but before its fetch lands.
+		key := batchKey(idList)
+		v, err, _ := c.sf.Do(key, func() (interface{}, error) {
+			return c.inner.GetProducts(context.Background(), idList)
+		})
+
+		w.err = err
+		if err == nil {
+			products := v.([]models.ProductInfo)
+			w.res = make(map[string]models.ProductInfo, len(products))
+			for _, p := range products {
+				w.res[p.ID] = p
+			}
+		}
+		close(w.done)
+	}()
+
+	return w, false
+}
+
+// batchKey builds a singleflight key from ids; ordering only needs to be
+// stable within a single process run, not across restarts.
+func batchKey(ids []string) string {
+	key := ""
+	for _, id := range ids {
+		key += id + "\x00"
+	}
+	return key
+}
+
+// put inserts or refreshes productID in the cache, evicting the
+// least-recently-used entry first if the cache is at maxSize.
+func (c *cachedProductClient) put(productID string, product models.ProductInfo) {
+	if c.maxSize <= 0 || c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[productID]; ok {
+		entry.product = product
+		entry.expiresAt = time.Now().Add(c.ttl)
+		c.order.MoveToFront(entry.elem)
+		return
+	}
+
+	if len(c.entries) >= c.maxSize {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry).productID)
+		}
+	}
+
+	entry := &cacheEntry{productID: productID, product: product, expiresAt: time.Now().Add(c.ttl)}
+	entry.elem = c.order.PushFront(entry)
+	c.entries[productID] = entry
+}
+
+func (c *cachedProductClient) recordHit() {
+	c.metricsMu.Lock()
+	c.metrics.Hits++
+	c.metricsMu.Unlock()
+}
+
+func (c *cachedProductClient) recordMiss() {
+	c.metricsMu.Lock()
+	c.metrics.Misses++
+	c.metricsMu.Unlock()
+}
+
+func (c *cachedProductClient) recordCoalesced() {
+	c.metricsMu.Lock()
+	c.metrics.Coalesced++
+	c.metricsMu.Unlock()
+}
+
+// Metrics returns a snapshot of the cache's cumulative hit/miss/coalesced
+// counts.
+func (c *cachedProductClient) Metrics() CachedProductClientMetrics {
+	c.metricsMu.Lock()
+	defer c.metricsMu.Unlock()
+	return c.metrics
+}
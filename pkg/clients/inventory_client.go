@@ -3,17 +3,35 @@ package clients
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 
 	dapr "github.com/dapr/go-sdk/client"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// ErrBatchEndpointUnavailable is returned by the *Batch methods when the
+// inventory service doesn't yet expose POST /api/v1/inventory/check-batch
+// (404/501), so callers can fall back to the per-SKU path against older
+// deployments.
+var ErrBatchEndpointUnavailable = errors.New("inventory batch endpoint unavailable")
+
+// SKUQty pairs a SKU with the quantity being checked, for the batch
+// availability methods.
+type SKUQty struct {
+	SKU      string
+	Quantity int
+}
+
 // InventoryClient interface for inventory service communication
 type InventoryClient interface {
 	CheckAvailability(ctx context.Context, sku string, quantity int) (bool, error)
+	CheckAvailabilityBatch(ctx context.Context, items []SKUQty) (map[string]bool, error)
 	GetAvailableQuantity(ctx context.Context, sku string) (int, error)
+	GetAvailableQuantitiesBatch(ctx context.Context, skus []string) (map[string]int, error)
 	ReserveStock(ctx context.Context, sku string, quantity int) error
 	ReleaseStock(ctx context.Context, sku string, quantity int) error
 }
@@ -69,6 +87,59 @@ func (c *inventoryClient) CheckAvailability(ctx context.Context, sku string, qua
 	return response.Success && response.Available, nil
 }
 
+// CheckAvailabilityBatch checks availability for several SKUs in one Dapr
+// invocation, so callers handling more than one item (bulk ops, cart
+// transfer, bundle adds) don't pay one round-trip per SKU. Returns
+// ErrBatchEndpointUnavailable if the inventory service doesn't support the
+// batch endpoint yet, so callers can fall back to CheckAvailability per SKU.
+func (c *inventoryClient) CheckAvailabilityBatch(ctx context.Context, items []SKUQty) (map[string]bool, error) {
+	methodPath := "/api/v1/inventory/check-batch"
+
+	type batchItem struct {
+		SKU      string `json:"sku"`
+		Quantity int    `json:"quantity"`
+	}
+	reqItems := make([]batchItem, 0, len(items))
+	for _, item := range items {
+		reqItems = append(reqItems, batchItem{SKU: item.SKU, Quantity: item.Quantity})
+	}
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{"items": reqItems})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	content := &dapr.DataContent{
+		Data:        bodyBytes,
+		ContentType: "application/json",
+	}
+	resp, err := c.daprClient.InvokeMethodWithContent(ctx, "inventory-service", methodPath, "POST", content)
+	if err != nil {
+		if isBatchEndpointUnavailable(err) {
+			return nil, ErrBatchEndpointUnavailable
+		}
+		c.logger.Error("Failed to invoke inventory service batch check via Dapr", zap.Error(err))
+		return nil, fmt.Errorf("failed to invoke inventory service: %w", err)
+	}
+
+	var response struct {
+		Success bool            `json:"success"`
+		Data    map[string]bool `json:"data"`
+		Message string          `json:"message"`
+	}
+
+	if err := json.Unmarshal(resp, &response); err != nil {
+		c.logger.Error("Failed to unmarshal inventory batch check response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("inventory service returned error: %s", response.Message)
+	}
+
+	return response.Data, nil
+}
+
 // GetAvailableQuantity gets the available quantity for a SKU using Dapr service invocation
 func (c *inventoryClient) GetAvailableQuantity(ctx context.Context, sku string) (int, error) {
 	// URL encode the SKU to handle special characters
@@ -112,6 +183,49 @@ func (c *inventoryClient) GetAvailableQuantity(ctx context.Context, sku string)
 	return response.Data.Quantity, nil
 }
 
+// GetAvailableQuantitiesBatch gets available quantities for several SKUs in
+// one Dapr invocation. Returns ErrBatchEndpointUnavailable if the inventory
+// service doesn't support the batch endpoint yet, so callers can fall back
+// to GetAvailableQuantity per SKU.
+func (c *inventoryClient) GetAvailableQuantitiesBatch(ctx context.Context, skus []string) (map[string]int, error) {
+	methodPath := "/api/v1/inventory/check-batch"
+
+	bodyBytes, err := json.Marshal(map[string][]string{"skus": skus})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	content := &dapr.DataContent{
+		Data:        bodyBytes,
+		ContentType: "application/json",
+	}
+	resp, err := c.daprClient.InvokeMethodWithContent(ctx, "inventory-service", methodPath, "POST", content)
+	if err != nil {
+		if isBatchEndpointUnavailable(err) {
+			return nil, ErrBatchEndpointUnavailable
+		}
+		c.logger.Error("Failed to invoke inventory service batch quantity check via Dapr", zap.Error(err))
+		return nil, fmt.Errorf("failed to invoke inventory service: %w", err)
+	}
+
+	var response struct {
+		Success bool           `json:"success"`
+		Data    map[string]int `json:"data"`
+		Message string         `json:"message"`
+	}
+
+	if err := json.Unmarshal(resp, &response); err != nil {
+		c.logger.Error("Failed to unmarshal inventory batch quantity response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("inventory service returned error: %s", response.Message)
+	}
+
+	return response.Data, nil
+}
+
 // ReserveStock reserves stock for a SKU (used during checkout) using Dapr service invocation
 func (c *inventoryClient) ReserveStock(ctx context.Context, sku string, quantity int) error {
 	// URL encode the SKU to handle special characters
@@ -175,3 +289,20 @@ func (c *inventoryClient) ReleaseStock(ctx context.Context, sku string, quantity
 
 	return nil
 }
+
+// isBatchEndpointUnavailable reports whether err indicates the invoked
+// inventory-service doesn't implement the batch endpoint (HTTP 404/501,
+// surfaced by Dapr's service invocation as a NotFound/Unimplemented gRPC
+// status), as opposed to a transient or unexpected failure.
+func isBatchEndpointUnavailable(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.NotFound, codes.Unimplemented:
+		return true
+	default:
+		return false
+	}
+}
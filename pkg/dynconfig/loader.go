@@ -0,0 +1,188 @@
+// Package dynconfig layers command-line flags, environment variables, and
+// config files into a single hot-reloadable Provider, in that precedence
+// order, falling back to caller-supplied defaults. It's modeled on
+// uber-go/fx's NewLoader/NewCommandLineProvider: build a Loader once at
+// startup with NewLoader, then Get/Watch keys from it instead of reading
+// internal/config.Config directly wherever a setting needs to change
+// without a restart (see config.LiveCartLimits for the pattern).
+package dynconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// Provider is the read/subscribe surface consumers depend on, satisfied by
+// *Loader. Depending on this interface rather than *Loader directly lets
+// tests substitute a fixed-value double.
+type Provider interface {
+	Get(key string) Value
+	Watch(key string, cb func(Value))
+}
+
+// Loader merges a fixed set of Sources, highest precedence first, into a
+// single snapshot. Load (and Reload) re-read every source and replace the
+// snapshot atomically; Watch registers a callback invoked with the new
+// Value for a key whenever Reload observes that key's value changed.
+type Loader struct {
+	sources []Source
+	paths   []string
+	logger  *zap.Logger
+
+	mu       sync.RWMutex
+	values   map[string]interface{}
+	watchers map[string][]func(Value)
+}
+
+// NewLoader builds a Loader from, in descending precedence: flags (typically
+// dynconfig.NewCommandLineProvider(os.Args[1:])), the process environment,
+// and the YAML/JSON files in paths, falling back to defaults for anything
+// none of those provide. It loads once synchronously before returning.
+func NewLoader(paths []string, flags Source, defaults map[string]interface{}, logger *zap.Logger) (*Loader, error) {
+	l := &Loader{
+		sources:  []Source{flags, NewEnvProvider(), NewFileProvider(paths), NewDefaultsProvider(defaults)},
+		paths:    paths,
+		logger:   logger,
+		values:   make(map[string]interface{}),
+		watchers: make(map[string][]func(Value)),
+	}
+	if err := l.Load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Paths returns the config file paths this Loader was built with.
+func (l *Loader) Paths() []string {
+	return l.paths
+}
+
+// Load re-reads every source and replaces the current snapshot. It does not
+// notify Watch callbacks; call Reload for that.
+func (l *Loader) Load() error {
+	next := make(map[string]interface{})
+
+	// Merge lowest precedence first so higher-precedence sources overwrite.
+	for i := len(l.sources) - 1; i >= 0; i-- {
+		values, err := l.sources[i].Load()
+		if err != nil {
+			return err
+		}
+		for k, v := range values {
+			next[k] = v
+		}
+	}
+
+	l.mu.Lock()
+	l.values = next
+	l.mu.Unlock()
+	return nil
+}
+
+// Get returns the current value for key, or a not-found Value if no source
+// provides it.
+func (l *Loader) Get(key string) Value {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if raw, ok := l.values[key]; ok {
+		return newValue(raw)
+	}
+	return Value{}
+}
+
+// Watch registers cb to be called with key's new Value whenever Reload
+// observes it changed. cb is not called for the value already in place at
+// registration time.
+func (l *Loader) Watch(key string, cb func(Value)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.watchers[key] = append(l.watchers[key], cb)
+}
+
+// Reload re-reads every source, then invokes the Watch callback for each
+// key whose value changed.
+func (l *Loader) Reload() error {
+	l.mu.RLock()
+	before := l.values
+	l.mu.RUnlock()
+
+	if err := l.Load(); err != nil {
+		return err
+	}
+
+	l.mu.RLock()
+	after := l.values
+	watchers := make(map[string][]func(Value), len(l.watchers))
+	for k, cbs := range l.watchers {
+		watchers[k] = cbs
+	}
+	l.mu.RUnlock()
+
+	for key, cbs := range watchers {
+		oldVal, oldOK := before[key]
+		newVal, newOK := after[key]
+		if oldOK == newOK && oldVal == newVal {
+			continue
+		}
+		v := l.Get(key)
+		for _, cb := range cbs {
+			cb(v)
+		}
+	}
+	return nil
+}
+
+// WatchFiles blocks, watching Paths() for writes via fsnotify and calling
+// Reload whenever one is observed, until ctx is done. Run it in its own
+// goroutine. It returns immediately (without error) if Paths() is empty.
+func (l *Loader) WatchFiles(ctx context.Context) error {
+	if len(l.paths) == 0 {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// fsnotify watches directories, not individual files (so it still picks
+	// up the atomic rename+replace many config-mount tools use), so collect
+	// the unique parent directories of Paths() instead.
+	dirs := make(map[string]struct{})
+	for _, p := range l.paths {
+		dirs[filepath.Dir(p)] = struct{}{}
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			l.logger.Warn("Failed to watch config directory", zap.String("dir", dir), zap.Error(err))
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if err := l.Reload(); err != nil {
+				l.logger.Error("Failed to reload config after file change", zap.String("file", event.Name), zap.Error(err))
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			l.logger.Error("Config file watcher error", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package dynconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source is one layer of configuration a Loader reads from. Load returns
+// every key/value pair the source currently provides; Loader merges sources
+// in precedence order (flags, then env, then files, then defaults) so a
+// higher-precedence source's keys win.
+type Source interface {
+	Load() (map[string]interface{}, error)
+}
+
+type mapSource struct {
+	values map[string]interface{}
+}
+
+func (s mapSource) Load() (map[string]interface{}, error) {
+	return s.values, nil
+}
+
+// NewDefaultsProvider wraps a static map of fallback values, meant to be the
+// lowest-precedence source passed to NewLoader.
+func NewDefaultsProvider(defaults map[string]interface{}) Source {
+	return mapSource{values: defaults}
+}
+
+// NewCommandLineProvider parses "--key=value" command-line arguments (e.g.
+// os.Args[1:]) into a Source, the highest-precedence layer in a Loader —
+// mirroring uber-go/fx's NewCommandLineProvider. Arguments without "="
+// (e.g. bare "--verbose" switches) are ignored, since dynconfig values are
+// always looked up by key rather than treated as boolean flags.
+func NewCommandLineProvider(args []string) Source {
+	values := make(map[string]interface{})
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		values[kv[0]] = kv[1]
+	}
+	return mapSource{values: values}
+}
+
+type envSource struct{}
+
+func (envSource) Load() (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 {
+			values[parts[0]] = parts[1]
+		}
+	}
+	return values, nil
+}
+
+// NewEnvProvider wraps the process environment as a Source.
+func NewEnvProvider() Source {
+	return envSource{}
+}
+
+type fileSource struct {
+	paths []string
+}
+
+// NewFileProvider reads a flat key/value map out of each YAML or JSON file
+// in paths (selected by extension), later paths overriding earlier ones. A
+// path that doesn't exist is skipped rather than an error, since config
+// files are optional overrides layered on top of env/defaults. Keys match
+// the same names as config.Load's environment variables (e.g.
+// "CART_MAX_ITEMS: 50"), so a mounted file can override the same settings
+// env vars do.
+func NewFileProvider(paths []string) Source {
+	return fileSource{paths: paths}
+}
+
+func (s fileSource) Load() (map[string]interface{}, error) {
+	merged := make(map[string]interface{})
+	for _, path := range s.paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+
+		values := make(map[string]interface{})
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".yaml", ".yml":
+			if err := yaml.Unmarshal(data, &values); err != nil {
+				return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+			}
+		case ".json":
+			if err := json.Unmarshal(data, &values); err != nil {
+				return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported config file extension %q for %s", ext, path)
+		}
+
+		for k, v := range values {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,135 @@
+package dynconfig
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value is an untyped configuration value with typed accessors, returned by
+// Provider.Get. The zero Value represents a missing key; every accessor
+// falls back to its defaultValue argument when the value is missing or
+// can't be converted to the requested type.
+type Value struct {
+	raw   interface{}
+	found bool
+}
+
+func newValue(raw interface{}) Value {
+	return Value{raw: raw, found: true}
+}
+
+// Found reports whether the key this Value came from was present in any
+// source.
+func (v Value) Found() bool {
+	return v.found
+}
+
+// String returns v as a string, or defaultValue if v is missing.
+func (v Value) String(defaultValue string) string {
+	if !v.found {
+		return defaultValue
+	}
+	if s, ok := v.raw.(string); ok {
+		return s
+	}
+	return defaultValue
+}
+
+// Int returns v as an int, or defaultValue if v is missing or not numeric.
+// JSON/YAML decoders hand back float64 for bare numbers, so that is
+// accepted alongside int and numeric strings.
+func (v Value) Int(defaultValue int) int {
+	if !v.found {
+		return defaultValue
+	}
+	switch n := v.raw.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	case string:
+		if parsed, err := strconv.Atoi(strings.TrimSpace(n)); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// Float returns v as a float64, or defaultValue if v is missing or not numeric.
+func (v Value) Float(defaultValue float64) float64 {
+	if !v.found {
+		return defaultValue
+	}
+	switch n := v.raw.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case string:
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// Bool returns v as a bool, or defaultValue if v is missing or not parseable.
+func (v Value) Bool(defaultValue bool) bool {
+	if !v.found {
+		return defaultValue
+	}
+	switch b := v.raw.(type) {
+	case bool:
+		return b
+	case string:
+		if parsed, err := strconv.ParseBool(strings.TrimSpace(b)); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// Duration returns v as a time.Duration, or defaultValue if v is missing or
+// not a valid duration string.
+func (v Value) Duration(defaultValue time.Duration) time.Duration {
+	if !v.found {
+		return defaultValue
+	}
+	switch d := v.raw.(type) {
+	case time.Duration:
+		return d
+	case string:
+		if parsed, err := time.ParseDuration(strings.TrimSpace(d)); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// StringSlice returns v as a slice of strings, or defaultValue if v is
+// missing. A plain string is split on commas, matching config.getSliceEnv's
+// existing "a,b,c" convention so env and file sources agree on format.
+func (v Value) StringSlice(defaultValue []string) []string {
+	if !v.found {
+		return defaultValue
+	}
+	switch s := v.raw.(type) {
+	case []string:
+		return s
+	case string:
+		if s == "" {
+			return defaultValue
+		}
+		return strings.Split(s, ",")
+	case []interface{}:
+		out := make([]string, 0, len(s))
+		for _, item := range s {
+			if str, ok := item.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return defaultValue
+}
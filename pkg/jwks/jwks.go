@@ -0,0 +1,213 @@
+// Package jwks discovers and caches a JSON Web Key Set from an OIDC
+// issuer, so middleware.AuthMiddleware can verify RS256/ES256-signed JWTs
+// against the issuer's current signing keys. Keys are re-fetched
+// periodically so a key rotation on the identity provider side doesn't
+// require a cart-service restart.
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// KeySet holds the signing keys published by an OIDC issuer, discovered
+// via its /.well-known/openid-configuration document.
+type KeySet struct {
+	issuer          string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+	logger          *zap.Logger
+
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+
+	stop chan struct{}
+}
+
+// NewKeySet creates a KeySet for the given OIDC issuer URL. Call Load once
+// during startup to populate it synchronously, then run Run in its own
+// goroutine to keep it fresh.
+func NewKeySet(issuer string, refreshInterval time.Duration, logger *zap.Logger) *KeySet {
+	if refreshInterval <= 0 {
+		refreshInterval = 10 * time.Minute
+	}
+
+	return &KeySet{
+		issuer:          issuer,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+		logger:          logger,
+		keys:            make(map[string]crypto.PublicKey),
+		stop:            make(chan struct{}),
+	}
+}
+
+// Load fetches the key set synchronously, for use during startup so the
+// service fails fast if the issuer is unreachable or misconfigured.
+func (k *KeySet) Load(ctx context.Context) error {
+	keys, err := k.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+
+	return nil
+}
+
+// Run blocks, re-fetching the key set on refreshInterval until ctx is done
+// or Stop is called.
+func (k *KeySet) Run(ctx context.Context) {
+	ticker := time.NewTicker(k.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-k.stop:
+			return
+		case <-ticker.C:
+			if err := k.Load(ctx); err != nil {
+				k.logger.Error("Failed to refresh JWKS", zap.String("issuer", k.issuer), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Stop ends a running KeySet loop.
+func (k *KeySet) Stop() {
+	close(k.stop)
+}
+
+// Key returns the public key for the given JWT "kid" header, if known.
+func (k *KeySet) Key(kid string) (crypto.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+type discoveryDocument struct {
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type rawJWKSet struct {
+	Keys []rawJWK `json:"keys"`
+}
+
+type rawJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *KeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var doc discoveryDocument
+	discoveryURL := strings.TrimSuffix(k.issuer, "/") + "/.well-known/openid-configuration"
+	if err := k.getJSON(ctx, discoveryURL, &doc); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %w", err)
+	}
+	if doc.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC discovery document at '%s' has no jwks_uri", discoveryURL)
+	}
+
+	var set rawJWKSet
+	if err := k.getJSON(ctx, doc.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, raw := range set.Keys {
+		key, err := raw.publicKey()
+		if err != nil {
+			k.logger.Warn("Skipping unsupported JWKS key",
+				zap.String("kid", raw.Kid), zap.String("kty", raw.Kty), zap.Error(err))
+			continue
+		}
+		keys[raw.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k *KeySet) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from '%s'", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// publicKey converts a raw JWK into a crypto.PublicKey, supporting the RSA
+// ("RS256") and EC P-256 ("ES256") key types OIDC providers commonly use.
+func (k rawJWK) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URLBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeBase64URLBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported EC curve '%s'", k.Crv)
+		}
+		x, err := decodeBase64URLBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeBase64URLBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type '%s'", k.Kty)
+	}
+}
+
+func decodeBase64URLBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
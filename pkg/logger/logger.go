@@ -1,11 +1,68 @@
 package logger
 
 import (
+	"context"
+	"time"
+
+	"github.com/aioutlet/cart-service/pkg/tracing"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // New creates a new logger instance
 func New(environment string) *zap.Logger {
+	logger, err := buildConfig(environment).Build(zap.AddCallerSkip(1))
+	if err != nil {
+		panic(err)
+	}
+
+	return logger
+}
+
+// NewWithOTLP creates a logger that writes to stdout like New, and also fans
+// every record out to an OTLP log exporter when cfg.Enabled and
+// cfg.LogEndpoint are set, so trace/span/correlation IDs logged as zap
+// fields also reach the log backend as a spec-compliant OTel log record
+// rather than only living in stdout JSON. High-volume messages ("HTTP
+// Request", "gRPC Request") are down-sampled at cfg.SampleRate on the OTLP
+// side the same way spans are, so turning sampling down doesn't flood the
+// log backend even though stdout still gets every line.
+//
+// The returned closer flushes and shuts down the OTLP exporter; call it
+// during graceful shutdown, after the last log line that matters.
+func NewWithOTLP(environment string, cfg tracing.TracingConfig) (*zap.Logger, func()) {
+	zapCfg := buildConfig(environment)
+	base, err := zapCfg.Build(zap.AddCallerSkip(1))
+	if err != nil {
+		panic(err)
+	}
+
+	if !cfg.Enabled || cfg.LogEndpoint == "" {
+		return base, func() {}
+	}
+
+	otelCore, shutdown, err := newOTLPCore(cfg, zapCfg.Level)
+	if err != nil {
+		base.Warn("Failed to initialize OTLP log exporter, logging to stdout only", zap.Error(err))
+		return base, func() {}
+	}
+
+	logger := base.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, otelCore)
+	}))
+
+	closer := func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(shutdownCtx); err != nil {
+			logger.Error("Failed to shut down OTLP log exporter", zap.Error(err))
+		}
+	}
+
+	return logger, closer
+}
+
+func buildConfig(environment string) zap.Config {
 	var config zap.Config
 
 	if environment == "production" {
@@ -19,10 +76,5 @@ func New(environment string) *zap.Logger {
 	config.OutputPaths = []string{"stdout"}
 	config.ErrorOutputPaths = []string{"stderr"}
 
-	logger, err := config.Build(zap.AddCallerSkip(1))
-	if err != nil {
-		panic(err)
-	}
-
-	return logger
+	return config
 }
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/aioutlet/cart-service/pkg/tracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// highVolumeMessages are the log lines emitted once per request
+// (middleware.Logger, grpc.UnaryLoggingInterceptor); everything else is
+// emitted to the OTLP exporter regardless of sample rate, same as how
+// InitTracing's sampler only thins out the request spans those lines
+// describe, not error/warn logging.
+var highVolumeMessages = map[string]struct{}{
+	"HTTP Request": {},
+	"gRPC Request": {},
+}
+
+// newOTLPCore builds a zapcore.Core that emits to cfg.LogEndpoint via
+// otlploggrpc, and a shutdown func for NewWithOTLP's closer.
+func newOTLPCore(cfg tracing.TracingConfig, level zapcore.LevelEnabler) (zapcore.Core, func(context.Context) error, error) {
+	exporter, err := otlploggrpc.New(context.Background(),
+		otlploggrpc.WithEndpoint(cfg.LogEndpoint),
+		otlploggrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create OTLP log exporter: %w", err)
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+
+	core := &otelCore{
+		logger:     provider.Logger(cfg.ServiceName),
+		level:      level,
+		sampleRate: cfg.SampleRate,
+	}
+
+	return core, provider.Shutdown, nil
+}
+
+// otelCore is a zapcore.Core that translates zap entries into OTel log
+// records. It's meant to be combined with the normal stdout core via
+// zapcore.NewTee, not used on its own.
+type otelCore struct {
+	logger     otellog.Logger
+	level      zapcore.LevelEnabler
+	sampleRate float64
+	fields     []zapcore.Field
+}
+
+func (c *otelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+func (c *otelCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field{}, c.fields...), fields...)
+	return &clone
+}
+
+func (c *otelCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *otelCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if _, highVolume := highVolumeMessages[entry.Message]; highVolume && !c.sampled() {
+		return nil
+	}
+
+	record := otellog.Record{}
+	record.SetTimestamp(entry.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(otelSeverity(entry.Level))
+	record.SetSeverityText(entry.Level.String())
+	record.SetBody(otellog.StringValue(entry.Message))
+
+	all := append(append([]zapcore.Field{}, c.fields...), fields...)
+	for _, f := range all {
+		record.AddAttributes(otelAttribute(f))
+	}
+
+	c.logger.Emit(context.Background(), record)
+	return nil
+}
+
+func (c *otelCore) Sync() error {
+	return nil
+}
+
+func (c *otelCore) sampled() bool {
+	if c.sampleRate >= 1 {
+		return true
+	}
+	if c.sampleRate <= 0 {
+		return false
+	}
+	return rand.Float64() < c.sampleRate
+}
+
+func otelSeverity(level zapcore.Level) otellog.Severity {
+	switch level {
+	case zapcore.DebugLevel:
+		return otellog.SeverityDebug
+	case zapcore.InfoLevel:
+		return otellog.SeverityInfo
+	case zapcore.WarnLevel:
+		return otellog.SeverityWarn
+	case zapcore.ErrorLevel:
+		return otellog.SeverityError
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return otellog.SeverityFatal1
+	case zapcore.FatalLevel:
+		return otellog.SeverityFatal
+	default:
+		return otellog.SeverityInfo
+	}
+}
+
+// otelAttribute converts one zap field into an OTel log key/value,
+// stringifying anything whose zap representation isn't one of the common
+// scalar types (strings, numbers, bools, durations, times, errors).
+func otelAttribute(f zapcore.Field) otellog.KeyValue {
+	switch f.Type {
+	case zapcore.StringType:
+		return otellog.String(f.Key, f.String)
+	case zapcore.BoolType:
+		return otellog.Bool(f.Key, f.Integer == 1)
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+		zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return otellog.Int64(f.Key, f.Integer)
+	case zapcore.Float64Type:
+		return otellog.Float64(f.Key, math.Float64frombits(uint64(f.Integer)))
+	case zapcore.Float32Type:
+		return otellog.Float64(f.Key, float64(math.Float32frombits(uint32(f.Integer))))
+	case zapcore.DurationType:
+		return otellog.String(f.Key, time.Duration(f.Integer).String())
+	case zapcore.TimeType:
+		ts := time.Unix(0, f.Integer)
+		if loc, ok := f.Interface.(*time.Location); ok {
+			ts = ts.In(loc)
+		}
+		return otellog.String(f.Key, ts.Format(time.RFC3339Nano))
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok {
+			return otellog.String(f.Key, err.Error())
+		}
+		return otellog.String(f.Key, "")
+	default:
+		return otellog.String(f.Key, fmt.Sprint(f.Interface))
+	}
+}
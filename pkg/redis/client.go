@@ -3,8 +3,14 @@ package redis
 import (
 	"context"
 	"fmt"
+	"net"
 
 	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/aioutlet/cart-service/pkg/tracing"
 )
 
 // RedisConfig holds Redis configuration
@@ -15,7 +21,9 @@ type RedisConfig struct {
 	PoolSize int
 }
 
-// NewClient creates a new Redis client
+// NewClient creates a new Redis client instrumented with an OpenTelemetry
+// tracing hook, so every command run against it produces a span under
+// whatever trace is active on the calling context.
 func NewClient(cfg RedisConfig) (*redis.Client, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:     cfg.Address,
@@ -24,6 +32,8 @@ func NewClient(cfg RedisConfig) (*redis.Client, error) {
 		PoolSize: cfg.PoolSize,
 	})
 
+	client.AddHook(newTracingHook(cfg.Address))
+
 	// Test the connection
 	ctx := context.Background()
 	_, err := client.Ping(ctx).Result()
@@ -33,3 +43,62 @@ func NewClient(cfg RedisConfig) (*redis.Client, error) {
 
 	return client, nil
 }
+
+// tracingHook is a redis.Hook that opens a span per command/pipeline,
+// recording db.system, db.statement, and net.peer.name attributes.
+type tracingHook struct {
+	peerName string
+}
+
+func newTracingHook(addr string) *tracingHook {
+	peer := addr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		peer = host
+	}
+	return &tracingHook{peerName: peer}
+}
+
+func (h *tracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, span := tracing.GetTracer().Start(ctx, "redis."+cmd.Name())
+	span.SetAttributes(
+		attribute.String("db.system", "redis"),
+		attribute.String("db.statement", cmd.String()),
+		attribute.String("net.peer.name", h.peerName),
+	)
+	return ctx, nil
+}
+
+func (h *tracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if err := cmd.Err(); err != nil && err != redis.Nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return nil
+}
+
+func (h *tracingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	ctx, span := tracing.GetTracer().Start(ctx, "redis.pipeline")
+	span.SetAttributes(
+		attribute.String("db.system", "redis"),
+		attribute.Int("db.redis.num_cmd", len(cmds)),
+		attribute.String("net.peer.name", h.peerName),
+	)
+	return ctx, nil
+}
+
+func (h *tracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && err != redis.Nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			break
+		}
+	}
+	return nil
+}
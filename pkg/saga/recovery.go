@@ -0,0 +1,141 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	dapr "github.com/dapr/go-sdk/client"
+	"go.uber.org/zap"
+)
+
+// CompensationFunc undoes one step, given the Data recorded alongside its
+// log entry when the step ran. Recovery resolves one of these by step name
+// for an orphaned step, since the original Step's closures died with the
+// process that ran it.
+type CompensationFunc func(ctx context.Context, data json.RawMessage) error
+
+// Recovery scans the saga pending index once at startup and compensates any
+// step logged as completed in a saga that never reached a terminal state —
+// e.g. the process crashed between reserving stock and persisting the cart.
+type Recovery struct {
+	client         dapr.Client
+	stateStoreName string
+	registry       map[string]CompensationFunc
+	logger         *zap.Logger
+}
+
+// NewRecovery creates a Recovery backed by the given Dapr client and state store.
+func NewRecovery(client dapr.Client, stateStoreName string, logger *zap.Logger) *Recovery {
+	return &Recovery{
+		client:         client,
+		stateStoreName: stateStoreName,
+		registry:       make(map[string]CompensationFunc),
+		logger:         logger,
+	}
+}
+
+// Register associates fn with stepName, so Recover can compensate an
+// orphaned step logged under that name. Must be called for every step name
+// a saga can produce before Recover runs.
+func (r *Recovery) Register(stepName string, fn CompensationFunc) {
+	r.registry[stepName] = fn
+}
+
+// Recover runs once, typically during startup: it reads the pending-saga
+// index and compensates every completed step of every saga still listed
+// there. A saga is only dropped from the index once every one of its steps
+// has compensated successfully; a saga with a step whose compensation
+// failed this pass is written back so the next Recover call (e.g. the next
+// process restart, or a future periodic caller) retries just that saga.
+// Safe to call with an empty index.
+func (r *Recovery) Recover(ctx context.Context) error {
+	item, err := r.client.GetState(ctx, r.stateStoreName, pendingIndexKey, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read saga pending index: %w", err)
+	}
+	if len(item.Value) == 0 {
+		return nil
+	}
+
+	var sagas []pendingSaga
+	if err := json.Unmarshal(item.Value, &sagas); err != nil {
+		return fmt.Errorf("failed to unmarshal saga pending index: %w", err)
+	}
+
+	remaining := make([]pendingSaga, 0, len(sagas))
+	for _, s := range sagas {
+		if !r.recoverSaga(ctx, s) {
+			remaining = append(remaining, s)
+		}
+	}
+
+	data, err := json.Marshal(remaining)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saga pending index: %w", err)
+	}
+	if err := r.client.SaveStateWithETag(ctx, r.stateStoreName, pendingIndexKey, data, item.Etag, nil); err != nil {
+		return fmt.Errorf("failed to update saga pending index: %w", err)
+	}
+
+	return nil
+}
+
+// recoverSaga compensates every step of s that the log shows as completed,
+// returning whether every one of them compensated successfully (so Recover
+// knows whether s can be dropped from the pending index). Failures are
+// logged rather than returned so one stuck saga doesn't block recovery of
+// the others.
+func (r *Recovery) recoverSaga(ctx context.Context, s pendingSaga) bool {
+	allOK := true
+	for _, stepName := range s.Steps {
+		item, err := r.client.GetState(ctx, r.stateStoreName, entryKey(s.SagaID, stepName), nil)
+		if err != nil {
+			r.logger.Warn("Failed to read saga step log entry during recovery",
+				zap.String("sagaID", s.SagaID), zap.String("step", stepName), zap.Error(err))
+			allOK = false
+			continue
+		}
+		if len(item.Value) == 0 {
+			continue
+		}
+
+		var entry logEntry
+		if err := json.Unmarshal(item.Value, &entry); err != nil {
+			r.logger.Warn("Failed to unmarshal saga step log entry during recovery",
+				zap.String("sagaID", s.SagaID), zap.String("step", stepName), zap.Error(err))
+			allOK = false
+			continue
+		}
+		if entry.Status != StatusCompleted {
+			continue
+		}
+
+		fn, ok := r.registry[stepName]
+		if !ok {
+			r.logger.Warn("No compensation registered for orphaned saga step",
+				zap.String("sagaID", s.SagaID), zap.String("step", stepName))
+			allOK = false
+			continue
+		}
+
+		if err := fn(ctx, entry.Data); err != nil {
+			r.logger.Error("Failed to compensate orphaned saga step",
+				zap.String("sagaID", s.SagaID), zap.String("step", stepName), zap.Error(err))
+			allOK = false
+			continue
+		}
+
+		entry.Status = StatusCompensated
+		if encoded, err := json.Marshal(entry); err == nil {
+			if err := r.client.SaveState(ctx, r.stateStoreName, entryKey(s.SagaID, stepName), encoded, nil); err != nil {
+				r.logger.Warn("Failed to persist saga step compensation during recovery",
+					zap.String("sagaID", s.SagaID), zap.String("step", stepName), zap.Error(err))
+			}
+		}
+
+		r.logger.Info("Compensated orphaned saga step on recovery",
+			zap.String("sagaID", s.SagaID), zap.String("step", stepName))
+	}
+	return allOK
+}
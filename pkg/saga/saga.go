@@ -0,0 +1,252 @@
+// Package saga implements a small saga orchestrator for operations that
+// span cart state and an external service (inventory), so a crash partway
+// through — e.g. after stock is reserved but before the cart is persisted —
+// can be recovered by compensating the steps that already succeeded instead
+// of leaking reservations. Each step's outcome is logged to a Dapr state
+// store keyed saga:{sagaID}:{stepName}; pkg/saga.Recovery replays that log
+// at startup for sagas an earlier process instance never finished.
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	dapr "github.com/dapr/go-sdk/client"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	logKeyPrefix    = "saga:"
+	pendingIndexKey = "saga:pending"
+)
+
+// Status is the lifecycle state of one saga step's log entry.
+type Status string
+
+const (
+	StatusStarted     Status = "started"
+	StatusCompleted   Status = "completed"
+	StatusCompensated Status = "compensated"
+)
+
+// Step is one unit of work in a saga. Data is recorded alongside the step's
+// log entry so Recovery can drive Compensate for an orphaned step even
+// without the original closures (see CompensationFunc); it's typically the
+// handful of fields Compensate itself needs, e.g. a SKU and quantity.
+type Step struct {
+	Name       string
+	Data       interface{}
+	Do         func(ctx context.Context) error
+	Compensate func(ctx context.Context) error
+}
+
+// logEntry is persisted at saga:{sagaID}:{step}, recording enough for
+// Recovery to compensate the step if the saga never reaches a terminal state.
+type logEntry struct {
+	SagaID    string          `json:"sagaId"`
+	Step      string          `json:"step"`
+	Status    Status          `json:"status"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// pendingSaga is one entry in the pending-saga index: the set of step names
+// a saga was started with, so Recovery knows which log entries to look for
+// without a state-store query capability (Dapr state stores generally don't
+// support listing keys by prefix; DaprCartRepository.ListAllCartKeys works
+// around the same limitation for carts via its own maintained index).
+type pendingSaga struct {
+	SagaID string   `json:"sagaId"`
+	Steps  []string `json:"steps"`
+}
+
+// Runner is the saga-execution surface consumed by internal/services,
+// satisfied by *Orchestrator in production and swapped for a test double in
+// unit tests.
+type Runner interface {
+	Run(ctx context.Context, sagaID string, steps []Step) error
+}
+
+// Orchestrator runs sagas against cart and inventory state, persisting a log
+// entry for each step to the given Dapr state store.
+type Orchestrator struct {
+	client         dapr.Client
+	stateStoreName string
+	logger         *zap.Logger
+}
+
+// NewOrchestrator creates an Orchestrator backed by the given Dapr client and state store.
+func NewOrchestrator(client dapr.Client, stateStoreName string, logger *zap.Logger) *Orchestrator {
+	return &Orchestrator{client: client, stateStoreName: stateStoreName, logger: logger}
+}
+
+// Run executes steps in order under sagaID. Before each step it logs a
+// "started" entry; if Do fails, Run compensates every prior step that
+// completed, in reverse order, removes sagaID from the pending index if every
+// compensation succeeded (otherwise leaves it there for Recovery), and
+// returns the original error wrapped with the failing step's name.
+func (o *Orchestrator) Run(ctx context.Context, sagaID string, steps []Step) error {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.Name
+	}
+	if err := o.addPending(ctx, sagaID, names); err != nil {
+		o.logger.Warn("Failed to record saga in pending index", zap.String("sagaID", sagaID), zap.Error(err))
+	}
+
+	completed := make([]Step, 0, len(steps))
+	for _, step := range steps {
+		data, err := json.Marshal(step.Data)
+		if err != nil {
+			return fmt.Errorf("saga %s step %s: failed to marshal step data: %w", sagaID, step.Name, err)
+		}
+
+		o.logEntry(ctx, sagaID, step.Name, StatusStarted, data)
+
+		if err := step.Do(ctx); err != nil {
+			if o.compensate(ctx, sagaID, completed) {
+				o.removePending(ctx, sagaID)
+			} else {
+				o.logger.Error("Saga compensation did not fully succeed; leaving saga in pending index for recovery",
+					zap.String("sagaID", sagaID))
+			}
+			return fmt.Errorf("saga %s step %s failed: %w", sagaID, step.Name, err)
+		}
+
+		o.logEntry(ctx, sagaID, step.Name, StatusCompleted, data)
+		completed = append(completed, step)
+	}
+
+	o.removePending(ctx, sagaID)
+	return nil
+}
+
+// compensate runs Compensate for each of steps, in reverse, logging (but not
+// returning) any individual failure so one stuck compensation doesn't block
+// the rest. It reports whether every step compensated successfully; Run only
+// drops sagaID from the pending index when this returns true, so a step
+// whose Compensate failed stays pending for Recovery to retry instead of
+// leaking silently.
+func (o *Orchestrator) compensate(ctx context.Context, sagaID string, steps []Step) bool {
+	allOK := true
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		if step.Compensate == nil {
+			continue
+		}
+		if err := step.Compensate(ctx); err != nil {
+			o.logger.Error("Saga compensation failed", zap.String("sagaID", sagaID), zap.String("step", step.Name), zap.Error(err))
+			allOK = false
+			continue
+		}
+
+		data, err := json.Marshal(step.Data)
+		if err != nil {
+			data = nil
+		}
+		o.logEntry(ctx, sagaID, step.Name, StatusCompensated, data)
+	}
+	return allOK
+}
+
+func (o *Orchestrator) logEntry(ctx context.Context, sagaID, stepName string, status Status, data json.RawMessage) {
+	entry := logEntry{SagaID: sagaID, Step: stepName, Status: status, Data: data, UpdatedAt: time.Now().UTC()}
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		o.logger.Warn("Failed to marshal saga log entry", zap.String("sagaID", sagaID), zap.String("step", stepName), zap.Error(err))
+		return
+	}
+	if err := o.client.SaveState(ctx, o.stateStoreName, entryKey(sagaID, stepName), encoded, nil); err != nil {
+		o.logger.Warn("Failed to persist saga log entry", zap.String("sagaID", sagaID), zap.String("step", stepName), zap.Error(err))
+	}
+}
+
+// addPending adds sagaID (with its step names) to the pending index.
+func (o *Orchestrator) addPending(ctx context.Context, sagaID string, steps []string) error {
+	return o.updatePendingIndex(ctx, func(sagas []pendingSaga) []pendingSaga {
+		for _, s := range sagas {
+			if s.SagaID == sagaID {
+				return sagas
+			}
+		}
+		return append(sagas, pendingSaga{SagaID: sagaID, Steps: steps})
+	})
+}
+
+// removePending removes sagaID from the pending index, logging (but not
+// returning) a failure, since callers invoke this after the saga has already
+// succeeded or been compensated.
+func (o *Orchestrator) removePending(ctx context.Context, sagaID string) {
+	err := o.updatePendingIndex(ctx, func(sagas []pendingSaga) []pendingSaga {
+		out := make([]pendingSaga, 0, len(sagas))
+		for _, s := range sagas {
+			if s.SagaID != sagaID {
+				out = append(out, s)
+			}
+		}
+		return out
+	})
+	if err != nil {
+		o.logger.Warn("Failed to remove saga from pending index", zap.String("sagaID", sagaID), zap.Error(err))
+	}
+}
+
+// updatePendingIndex applies mutate to the pending-saga index and saves it
+// back with an ETag check, retrying on conflict the same way
+// cartService.saveCartWithRetry does for carts.
+func (o *Orchestrator) updatePendingIndex(ctx context.Context, mutate func([]pendingSaga) []pendingSaga) error {
+	const maxAttempts = 3
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		item, err := o.client.GetState(ctx, o.stateStoreName, pendingIndexKey, nil)
+		if err != nil {
+			return fmt.Errorf("failed to read saga pending index: %w", err)
+		}
+
+		var sagas []pendingSaga
+		if len(item.Value) > 0 {
+			if err := json.Unmarshal(item.Value, &sagas); err != nil {
+				return fmt.Errorf("failed to unmarshal saga pending index: %w", err)
+			}
+		}
+
+		data, err := json.Marshal(mutate(sagas))
+		if err != nil {
+			return fmt.Errorf("failed to marshal saga pending index: %w", err)
+		}
+
+		err = o.client.SaveStateWithETag(ctx, o.stateStoreName, pendingIndexKey, data, item.Etag, nil)
+		if err == nil {
+			return nil
+		}
+		if !isETagConflict(err) {
+			return fmt.Errorf("failed to save saga pending index: %w", err)
+		}
+	}
+
+	return fmt.Errorf("failed to update saga pending index after %d attempts", maxAttempts)
+}
+
+func entryKey(sagaID, stepName string) string {
+	return logKeyPrefix + sagaID + ":" + stepName
+}
+
+// isETagConflict reports whether err indicates SaveStateWithETag was
+// rejected because the stored ETag no longer matches, as opposed to a
+// transient or unexpected failure.
+func isETagConflict(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Aborted, codes.FailedPrecondition:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"go.uber.org/zap"
+)
+
+// AWSConfig configures an AWSSecretProvider's target region and secret.
+type AWSConfig struct {
+	Region   string
+	SecretID string
+}
+
+// AWSSecretProvider retrieves secrets from AWS Secrets Manager. The secret
+// at SecretID is expected to hold a flat JSON object of key/value pairs,
+// mirroring how DaprSecretProvider and VaultSecretProvider expose several
+// named secrets behind one lookup.
+type AWSSecretProvider struct {
+	client   *secretsmanager.Client
+	secretID string
+	logger   *zap.Logger
+}
+
+// NewAWSSecretProvider creates an AWSSecretProvider using the default AWS
+// credential chain (environment, shared config, or instance/task role).
+func NewAWSSecretProvider(ctx context.Context, cfg AWSConfig, logger *zap.Logger) (*AWSSecretProvider, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &AWSSecretProvider{
+		client:   secretsmanager.NewFromConfig(awsCfg),
+		secretID: cfg.SecretID,
+		logger:   logger,
+	}, nil
+}
+
+// GetSecret fetches and parses the JSON secret value, returning the value
+// stored under key.
+func (p *AWSSecretProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	out, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(p.secretID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve secret '%s' from AWS Secrets Manager: %w", p.secretID, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret '%s' has no string value", p.secretID)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(*out.SecretString), &values); err != nil {
+		return "", fmt.Errorf("failed to parse AWS secret '%s' as JSON: %w", p.secretID, err)
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("secret '%s' not found in AWS secret '%s'", key, p.secretID)
+	}
+
+	return value, nil
+}
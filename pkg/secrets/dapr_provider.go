@@ -0,0 +1,48 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	dapr "github.com/dapr/go-sdk/client"
+	"go.uber.org/zap"
+)
+
+// DaprSecretProvider retrieves secrets from a Dapr Secret Store component.
+type DaprSecretProvider struct {
+	client      dapr.Client
+	secretStore string
+	logger      *zap.Logger
+}
+
+// NewDaprSecretProvider creates a DaprSecretProvider bound to the named
+// secret store component.
+func NewDaprSecretProvider(client dapr.Client, secretStore string, logger *zap.Logger) *DaprSecretProvider {
+	return &DaprSecretProvider{
+		client:      client,
+		secretStore: secretStore,
+		logger:      logger,
+	}
+}
+
+// GetSecret retrieves a secret from the Dapr Secret Store.
+func (p *DaprSecretProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	secrets, err := p.client.GetSecret(ctx, p.secretStore, key, nil)
+	if err != nil {
+		p.logger.Error("Failed to retrieve secret from Dapr",
+			zap.String("key", key),
+			zap.String("store", p.secretStore),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to retrieve secret '%s': %w", key, err)
+	}
+
+	value, ok := secrets[key]
+	if !ok {
+		p.logger.Error("Secret key not found in response",
+			zap.String("key", key),
+			zap.String("store", p.secretStore))
+		return "", fmt.Errorf("secret '%s' not found in store '%s'", key, p.secretStore)
+	}
+
+	return value, nil
+}
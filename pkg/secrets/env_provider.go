@@ -0,0 +1,27 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// EnvSecretProvider reads secrets directly from process environment
+// variables. It's the fallback backend for local development, or for
+// deployments that inject secrets via the environment rather than an
+// external secret store.
+type EnvSecretProvider struct{}
+
+// NewEnvSecretProvider creates an EnvSecretProvider.
+func NewEnvSecretProvider() *EnvSecretProvider {
+	return &EnvSecretProvider{}
+}
+
+// GetSecret returns the value of the environment variable named key.
+func (p *EnvSecretProvider) GetSecret(_ context.Context, key string) (string, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return "", fmt.Errorf("environment variable '%s' is not set", key)
+	}
+	return value, nil
+}
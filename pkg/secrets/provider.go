@@ -0,0 +1,15 @@
+// Package secrets abstracts secret retrieval behind a single SecretProvider
+// interface, so cart-service can move between Dapr, HashiCorp Vault, AWS
+// Secrets Manager, and a local env-var fallback via configuration alone.
+package secrets
+
+import "context"
+
+// SecretProvider retrieves a named secret value from a single backing
+// store. Implementations are selected by SecretsConfig.Backend; callers
+// that need rotation (e.g. AuthMiddleware validating JWTs) should wrap a
+// SecretProvider in a Rotator rather than caching GetSecret results
+// themselves.
+type SecretProvider interface {
+	GetSecret(ctx context.Context, key string) (string, error)
+}
@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RotatorConfig controls which secret Rotator tracks, how often it
+// refreshes, and how long a just-rotated secret keeps validating
+// alongside the new one.
+type RotatorConfig struct {
+	Key             string
+	RefreshInterval time.Duration
+	RotationWindow  time.Duration
+}
+
+// Rotator polls a SecretProvider on a fixed interval (or on demand via
+// Invalidate) and holds both the current and immediately-previous secret
+// value for RotationWindow after a rotation, so requests signed with the
+// old secret don't all start failing the instant a new one is published.
+// Reads and writes are synchronized with a mutex so callers always see a
+// consistent pair, replacing the old sync.Once-cached, never-refreshed
+// jwtSecretCache in middleware.
+type Rotator struct {
+	provider SecretProvider
+	cfg      RotatorConfig
+	logger   *zap.Logger
+
+	mu        sync.RWMutex
+	current   string
+	previous  string
+	rotatedAt time.Time
+
+	invalidate chan struct{}
+	stop       chan struct{}
+}
+
+// NewRotator creates a Rotator. Call Load once during startup to populate
+// the initial secret synchronously, then run Run in its own goroutine.
+func NewRotator(provider SecretProvider, cfg RotatorConfig, logger *zap.Logger) *Rotator {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = 5 * time.Minute
+	}
+
+	return &Rotator{
+		provider:   provider,
+		cfg:        cfg,
+		logger:     logger,
+		invalidate: make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Load fetches the secret synchronously, for use during startup so the
+// service fails fast if the configured backend is unreachable.
+func (r *Rotator) Load(ctx context.Context) error {
+	return r.refresh(ctx)
+}
+
+// Run blocks, refreshing the secret on cfg.RefreshInterval or whenever
+// Invalidate is called, until ctx is done or Stop is called.
+func (r *Rotator) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stop:
+			return
+		case <-r.invalidate:
+			if err := r.refresh(ctx); err != nil {
+				r.logger.Error("Failed to refresh secret on invalidation", zap.String("key", r.cfg.Key), zap.Error(err))
+			}
+		case <-ticker.C:
+			if err := r.refresh(ctx); err != nil {
+				r.logger.Error("Failed to refresh secret", zap.String("key", r.cfg.Key), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Stop ends a running Rotator loop.
+func (r *Rotator) Stop() {
+	close(r.stop)
+}
+
+// Invalidate requests an immediate refresh rather than waiting for the
+// next tick.
+func (r *Rotator) Invalidate() {
+	select {
+	case r.invalidate <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Rotator) refresh(ctx context.Context) error {
+	value, err := r.provider.GetSecret(ctx, r.cfg.Key)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	if r.current != "" && value != r.current {
+		r.previous = r.current
+		r.rotatedAt = time.Now()
+		r.logger.Info("Secret rotated", zap.String("key", r.cfg.Key))
+	}
+	r.current = value
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Secrets returns the secret value(s) that should currently validate a
+// token: just the current one, or both current and previous while still
+// inside the post-rotation window.
+func (r *Rotator) Secrets() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.previous != "" && time.Since(r.rotatedAt) < r.cfg.RotationWindow {
+		return []string{r.current, r.previous}
+	}
+	return []string{r.current}
+}
@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"go.uber.org/zap"
+)
+
+// VaultConfig configures a VaultSecretProvider's connection and auth
+// method. Set RoleID and SecretID to authenticate via AppRole (preferred
+// for long-running services, since the resulting token can be renewed
+// without a human re-entering credentials); otherwise Token is used
+// directly.
+type VaultConfig struct {
+	Address    string
+	Token      string
+	RoleID     string
+	SecretID   string
+	SecretPath string // KV v2 path, e.g. "secret/data/cart-service"
+}
+
+// VaultSecretProvider retrieves secrets from HashiCorp Vault's KV v2 engine.
+type VaultSecretProvider struct {
+	client     *vaultapi.Client
+	secretPath string
+	logger     *zap.Logger
+}
+
+// NewVaultSecretProvider creates a VaultSecretProvider, authenticating via
+// AppRole when cfg.RoleID/cfg.SecretID are set, or using cfg.Token directly
+// otherwise.
+func NewVaultSecretProvider(cfg VaultConfig, logger *zap.Logger) (*VaultSecretProvider, error) {
+	clientCfg := vaultapi.DefaultConfig()
+	clientCfg.Address = cfg.Address
+
+	client, err := vaultapi.NewClient(clientCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault client: %w", err)
+	}
+
+	if cfg.RoleID != "" && cfg.SecretID != "" {
+		if err := authenticateAppRole(client, cfg.RoleID, cfg.SecretID); err != nil {
+			return nil, fmt.Errorf("failed to authenticate with Vault AppRole: %w", err)
+		}
+	} else {
+		client.SetToken(cfg.Token)
+	}
+
+	return &VaultSecretProvider{
+		client:     client,
+		secretPath: cfg.SecretPath,
+		logger:     logger,
+	}, nil
+}
+
+func authenticateAppRole(client *vaultapi.Client, roleID, secretID string) error {
+	secret, err := client.Logical().Write("auth/approle/login", map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+	if err != nil {
+		return err
+	}
+	if secret == nil || secret.Auth == nil {
+		return fmt.Errorf("vault approle login returned no auth info")
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return nil
+}
+
+// GetSecret reads key from the KV v2 secret at cfg.SecretPath. If the
+// client's token is nearing expiry it's renewed first so long-lived
+// AppRole sessions don't lapse mid-process.
+func (p *VaultSecretProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	if err := p.renewTokenIfNeeded(ctx); err != nil {
+		p.logger.Warn("Failed to renew Vault token, continuing with existing token", zap.Error(err))
+	}
+
+	secret, err := p.client.Logical().ReadWithContext(ctx, p.secretPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret from Vault path '%s': %w", p.secretPath, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("secret path '%s' not found in Vault", p.secretPath)
+	}
+
+	// KV v2 nests the actual key/value pairs under a "data" field.
+	data, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unexpected Vault KV response shape at '%s'", p.secretPath)
+	}
+
+	value, ok := data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("secret '%s' not found at Vault path '%s'", key, p.secretPath)
+	}
+
+	return value, nil
+}
+
+// renewTokenIfNeeded looks up the client's own token and renews it once its
+// remaining TTL is 30 minutes or less.
+func (p *VaultSecretProvider) renewTokenIfNeeded(ctx context.Context) error {
+	info, err := p.client.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to look up Vault token: %w", err)
+	}
+
+	ttl, err := info.TokenTTL()
+	if err != nil {
+		return fmt.Errorf("failed to read Vault token TTL: %w", err)
+	}
+	renewable, _ := info.Data["renewable"].(bool)
+	if !renewable || ttl > 30*time.Minute {
+		return nil
+	}
+
+	_, err = p.client.Auth().Token().RenewSelfWithContext(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to renew Vault token: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// CartMetrics holds the OpenTelemetry instruments CartService records
+// against. It's deliberately small: counters/histograms that track outcomes
+// an operator would alert or dashboard on, not a catch-all for every
+// internal step (StepTrace and the span attributes already cover
+// fine-grained timing).
+type CartMetrics struct {
+	// ItemsAdded counts items successfully added to a cart via AddItem.
+	ItemsAdded metric.Int64Counter
+
+	// LockAcquireDuration records how long CartLocker.Acquire takes,
+	// in seconds, so lock contention shows up in dashboards before it
+	// shows up as request timeouts.
+	LockAcquireDuration metric.Float64Histogram
+
+	// ValidationRemovedItems counts items ValidateCart drops from a cart
+	// because the product became unavailable or went out of stock.
+	ValidationRemovedItems metric.Int64Counter
+}
+
+// NewCartMetrics builds a CartMetrics from mp. A nil mp falls back to the
+// global MeterProvider (otel.GetMeterProvider()), which is a no-op until a
+// metrics SDK sets one (InitTracing sets only the TracerProvider), so callers that don't
+// care about metrics yet can pass nil safely.
+func NewCartMetrics(mp metric.MeterProvider) (*CartMetrics, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter("cart-service")
+
+	itemsAdded, err := meter.Int64Counter(
+		"cart_items_added_total",
+		metric.WithDescription("Number of items successfully added to carts"),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cart_items_added_total counter: %w", err)
+	}
+
+	lockAcquireDuration, err := meter.Float64Histogram(
+		"cart_lock_acquire_duration_seconds",
+		metric.WithDescription("Time spent acquiring a cart lock"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cart_lock_acquire_duration_seconds histogram: %w", err)
+	}
+
+	validationRemovedItems, err := meter.Int64Counter(
+		"cart_validation_removed_items_total",
+		metric.WithDescription("Number of items removed from carts by ValidateCart due to unavailable or out-of-stock products"),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cart_validation_removed_items_total counter: %w", err)
+	}
+
+	return &CartMetrics{
+		ItemsAdded:             itemsAdded,
+		LockAcquireDuration:    lockAcquireDuration,
+		ValidationRemovedItems: validationRemovedItems,
+	}, nil
+}
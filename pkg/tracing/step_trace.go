@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StepTrace records per-step timings for a single operation (e.g. marshal,
+// Dapr call, unmarshal, expiry check), the way k8s.io/utils/trace's Trace
+// does for kube-apiserver requests: steps are cheap to record unconditionally,
+// and LogIfLong only actually logs them once the operation's total duration
+// turns out to exceed a threshold, so a log line only appears for the
+// requests worth investigating rather than on every call.
+type StepTrace struct {
+	logger *zap.Logger
+	op     string
+	userID string
+	start  time.Time
+	last   time.Time
+	steps  []stepTiming
+}
+
+type stepTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// NewStepTrace starts timing op (e.g. "cart.get_cart") for userID.
+func NewStepTrace(logger *zap.Logger, op, userID string) *StepTrace {
+	now := time.Now()
+	return &StepTrace{logger: logger, op: op, userID: userID, start: now, last: now}
+}
+
+// Step records that the step named name just finished, timed from the
+// previous Step call (or from NewStepTrace, for the first step).
+func (t *StepTrace) Step(name string) {
+	now := time.Now()
+	t.steps = append(t.steps, stepTiming{name: name, duration: now.Sub(t.last)})
+	t.last = now
+}
+
+// LogIfLong logs one line with every recorded step's duration if the
+// operation's total duration (from NewStepTrace to this call) is at least
+// threshold; otherwise it's a no-op.
+func (t *StepTrace) LogIfLong(threshold time.Duration) {
+	total := time.Since(t.start)
+	if total < threshold {
+		return
+	}
+
+	fields := make([]zap.Field, 0, len(t.steps)+3)
+	fields = append(fields,
+		zap.String("op", t.op),
+		zap.String("userID", t.userID),
+		zap.Duration("total", total))
+	for _, step := range t.steps {
+		fields = append(fields, zap.Duration(step.name, step.duration))
+	}
+
+	t.logger.Warn("Slow cart operation", fields...)
+}
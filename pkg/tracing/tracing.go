@@ -2,11 +2,20 @@ package tracing
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -15,36 +24,69 @@ import (
 	"go.uber.org/zap"
 )
 
-// TracingConfig holds the configuration for distributed tracing
+// ExporterType selects which span exporter InitTracing wires up.
+type ExporterType string
+
+const (
+	ExporterOTLPGRPC ExporterType = "otlp-grpc"
+	ExporterOTLPHTTP ExporterType = "otlp-http"
+	ExporterJaeger   ExporterType = "jaeger"
+	ExporterStdout   ExporterType = "stdout"
+	ExporterNone     ExporterType = "none"
+)
+
+// TracingConfig holds the configuration for distributed tracing.
+//
+// Headers, Insecure, and Compression are only consulted for the "otlp-grpc"
+// and "otlp-http" exporters; Endpoint is also used by "jaeger" (see
+// newJaegerExporter). For the OTLP exporters, when Endpoint is empty, the
+// underlying otlptrace exporter falls back to the standard
+// OTEL_EXPORTER_OTLP_* environment variables (OTEL_EXPORTER_OTLP_ENDPOINT,
+// OTEL_EXPORTER_OTLP_HEADERS, OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, etc.), so
+// operators can point the service at a Collector, Tempo, or Honeycomb
+// without a code change.
 type TracingConfig struct {
-	ServiceName     string
-	ServiceVersion  string
-	Environment     string
-	JaegerEndpoint  string
-	Enabled         bool
-	SampleRate      float64
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+	ExporterType   ExporterType
+	Endpoint       string
+	Headers        map[string]string
+	Insecure       bool
+	Compression    string
+	Enabled        bool
+	SampleRate     float64
+
+	// LogEndpoint is the OTLP gRPC endpoint logger.NewWithOTLP exports log
+	// records to. It's separate from Endpoint (traces) since most
+	// deployments point logs at a different Collector pipeline/backend;
+	// left empty, NewWithOTLP skips OTLP entirely and logs to stdout only.
+	LogEndpoint string
+
+	// Sampler overrides the sdktrace.Sampler built from SampleRate, for
+	// callers that need the ratio to change at runtime (see DynamicSampler).
+	// Left nil, InitTracing falls back to a static
+	// sdktrace.TraceIDRatioBased(SampleRate).
+	Sampler sdktrace.Sampler
 }
 
-// InitTracing initializes OpenTelemetry with Jaeger exporter
+// InitTracing initializes OpenTelemetry with the span exporter selected by
+// cfg.ExporterType.
 func InitTracing(cfg TracingConfig, logger *zap.Logger) (*sdktrace.TracerProvider, error) {
-	if !cfg.Enabled {
+	if !cfg.Enabled || cfg.ExporterType == ExporterNone {
 		logger.Info("Distributed tracing is disabled")
 		return sdktrace.NewTracerProvider(), nil
 	}
 
-	// Create Jaeger exporter
-	jaegerExporter, err := jaeger.New(
-		jaeger.WithCollectorEndpoint(
-			jaeger.WithEndpoint(cfg.JaegerEndpoint),
-		),
-	)
+	ctx := context.Background()
+
+	exporter, err := newSpanExporter(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Jaeger exporter: %w", err)
+		return nil, fmt.Errorf("failed to create span exporter: %w", err)
 	}
 
-	// Create resource with service information
 	res, err := resource.New(
-		context.Background(),
+		ctx,
 		resource.WithAttributes(
 			semconv.ServiceName(cfg.ServiceName),
 			semconv.ServiceVersion(cfg.ServiceVersion),
@@ -55,14 +97,17 @@ func InitTracing(cfg TracingConfig, logger *zap.Logger) (*sdktrace.TracerProvide
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
-	// Create tracer provider
+	sampler := cfg.Sampler
+	if sampler == nil {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRate)
+	}
+
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(jaegerExporter),
+		sdktrace.WithBatcher(exporter),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(cfg.SampleRate)),
+		sdktrace.WithSampler(sampler),
 	)
 
-	// Set global tracer provider
 	otel.SetTracerProvider(tp)
 
 	// Set global propagator to handle trace context and baggage
@@ -77,12 +122,86 @@ func InitTracing(cfg TracingConfig, logger *zap.Logger) (*sdktrace.TracerProvide
 		zap.String("service", cfg.ServiceName),
 		zap.String("version", cfg.ServiceVersion),
 		zap.String("environment", cfg.Environment),
-		zap.String("jaeger_endpoint", cfg.JaegerEndpoint),
+		zap.String("exporter_type", string(cfg.ExporterType)),
+		zap.String("endpoint", cfg.Endpoint),
 		zap.Float64("sample_rate", cfg.SampleRate))
 
 	return tp, nil
 }
 
+// newSpanExporter builds the span exporter selected by cfg.ExporterType.
+func newSpanExporter(ctx context.Context, cfg TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.ExporterType {
+	case ExporterOTLPGRPC:
+		return newOTLPGRPCExporter(ctx, cfg)
+	case ExporterOTLPHTTP:
+		return newOTLPHTTPExporter(ctx, cfg)
+	case ExporterJaeger:
+		return newJaegerExporter(cfg)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter type: %q", cfg.ExporterType)
+	}
+}
+
+// newJaegerExporter builds a Jaeger collector exporter. cfg.Endpoint is the
+// collector's HTTP endpoint (e.g. "http://jaeger:14268/api/traces"); when
+// empty, the underlying exporter falls back to the standard
+// OTEL_EXPORTER_JAEGER_ENDPOINT environment variable.
+func newJaegerExporter(cfg TracingConfig) (*jaeger.Exporter, error) {
+	var opts []jaeger.CollectorEndpointOption
+	if cfg.Endpoint != "" {
+		opts = append(opts, jaeger.WithEndpoint(cfg.Endpoint))
+	}
+	return jaeger.New(jaeger.WithCollectorEndpoint(opts...))
+}
+
+func newOTLPGRPCExporter(ctx context.Context, cfg TracingConfig) (*otlptrace.Exporter, error) {
+	opts := []otlptracegrpc.Option{}
+
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+	if compression := parseGRPCCompression(cfg.Compression); compression != "" {
+		opts = append(opts, otlptracegrpc.WithCompressor(compression))
+	}
+
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+func newOTLPHTTPExporter(ctx context.Context, cfg TracingConfig) (*otlptrace.Exporter, error) {
+	opts := []otlptracehttp.Option{}
+
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	if strings.EqualFold(cfg.Compression, "gzip") {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+
+	return otlptracehttp.New(ctx, opts...)
+}
+
+func parseGRPCCompression(compression string) string {
+	if strings.EqualFold(compression, "gzip") {
+		return "gzip"
+	}
+	return ""
+}
+
 // Shutdown gracefully shuts down the tracer provider
 func Shutdown(ctx context.Context, tp *sdktrace.TracerProvider, logger *zap.Logger) {
 	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -99,3 +218,120 @@ func Shutdown(ctx context.Context, tp *sdktrace.TracerProvider, logger *zap.Logg
 func GetTracer() trace.Tracer {
 	return otel.Tracer("cart-service")
 }
+
+// WithCartSpan runs fn inside a span named "cart."+op, tagging it with
+// cart.user_id. fn may call SetCartTotalItems(ctx, n) to record the
+// resulting item count once it knows it.
+//
+// expectedErrs are domain errors (e.g. models.ErrMaxQuantityExceeded) that
+// represent normal cart validation outcomes rather than operational
+// failures: when fn returns one of them, it is recorded as a span event
+// so it stays visible for debugging without tripping error-rate alerts.
+// Any other error sets the span status to Error.
+func WithCartSpan(ctx context.Context, op, userID string, expectedErrs []error, fn func(context.Context) error) error {
+	ctx, span := GetTracer().Start(ctx, "cart."+op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("cart.user_id", userID),
+		semconv.EnduserID(userID),
+	)
+
+	err := fn(ctx)
+	if err == nil {
+		return nil
+	}
+
+	for _, expected := range expectedErrs {
+		if errors.Is(err, expected) {
+			span.AddEvent("cart.validation_error", trace.WithAttributes(
+				attribute.String("cart.error", err.Error()),
+			))
+			return err
+		}
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
+
+// StartCartSpan starts a span named "cart."+op for callers that need to wrap
+// a whole method body (including early returns) rather than a single
+// closure, e.g. via `ctx, span := StartCartSpan(ctx, "get_cart", userID)` and
+// `defer func() { EndSpan(span, err) }()` with err bound by a named return.
+// Prefer WithCartSpan when the method's errors need to be classified into
+// expected-vs-operational; StartCartSpan/EndSpan always record any non-nil
+// error as a true span error.
+func StartCartSpan(ctx context.Context, op, userID string) (context.Context, trace.Span) {
+	ctx, span := GetTracer().Start(ctx, "cart."+op)
+	span.SetAttributes(
+		attribute.String("cart.user_id", userID),
+		semconv.EnduserID(userID),
+	)
+	return ctx, span
+}
+
+// EndSpan records err on span, if non-nil, and ends it. Pair with
+// StartCartSpan via defer.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetCartTotalItems records the cart's item count on the span active in ctx.
+// Call it from within a WithCartSpan callback once the resulting cart is known.
+//
+// cart.items_count is set alongside the pre-existing cart.total_items for
+// the same reason dapr_client.go sets both net.peer.name and peer.service:
+// it's an additional, differently-named attribute some collector configs
+// key on, not a replacement for the established one.
+func SetCartTotalItems(ctx context.Context, totalItems int) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("cart.total_items", totalItems),
+		attribute.Int("cart.items_count", totalItems),
+	)
+}
+
+// SetCartTotalPrice records the cart's total price on the span active in
+// ctx. Call it alongside SetCartTotalItems from within a WithCartSpan
+// callback once the resulting cart is known.
+func SetCartTotalPrice(ctx context.Context, totalPrice float64) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Float64("cart.total_price", totalPrice))
+}
+
+// DynamicSampler is an sdktrace.Sampler whose ratio can be changed after
+// InitTracing has already built the TracerProvider, for operators tuning
+// sampling in response to load without a restart (see config.LiveCartLimits
+// for the same pattern applied to cart limits). Bind it to a
+// dynconfig.Provider watching "TRACING_SAMPLE_RATE" and call Update from the
+// watch callback.
+type DynamicSampler struct {
+	ratio atomic.Value // float64
+}
+
+// NewDynamicSampler creates a DynamicSampler starting at initialRatio.
+func NewDynamicSampler(initialRatio float64) *DynamicSampler {
+	s := &DynamicSampler{}
+	s.ratio.Store(initialRatio)
+	return s
+}
+
+// Update changes the ratio used by subsequent ShouldSample calls.
+func (s *DynamicSampler) Update(ratio float64) {
+	s.ratio.Store(ratio)
+}
+
+// ShouldSample implements sdktrace.Sampler by delegating to a
+// TraceIDRatioBased sampler built from the current ratio.
+func (s *DynamicSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.TraceIDRatioBased(s.ratio.Load().(float64)).ShouldSample(params)
+}
+
+// Description implements sdktrace.Sampler.
+func (s *DynamicSampler) Description() string {
+	return fmt.Sprintf("DynamicSampler{ratio=%v}", s.ratio.Load().(float64))
+}
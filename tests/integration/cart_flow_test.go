@@ -0,0 +1,217 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/pkg/clients"
+	"github.com/aioutlet/cart-service/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests drive env.Router over real HTTP against a real Redis-backed
+// Dapr state store and distributed lock, exercising the same AddItem /
+// UpdateItem / RemoveItem / TransferCart / ClearCart scenarios the unit
+// tests in tests/unit/services/cart_service_test.go cover against
+// mocks.MockCartRepository, but through the full Dapr state store and
+// fencing-token path (see CartRepository.AcquireLockWithToken). They need
+// Docker and are excluded from the default `go test ./...` run.
+func doRequest(t *testing.T, env *testutils.IntegrationEnv, method, path, userID string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reqBody *bytes.Buffer
+	if body != nil {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		reqBody = bytes.NewBuffer(raw)
+	} else {
+		reqBody = bytes.NewBuffer(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reqBody)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User-Id", userID)
+
+	w := httptest.NewRecorder()
+	env.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIntegration_AddItem(t *testing.T) {
+	env, cleanup := testutils.NewIntegrationEnv(t)
+	defer cleanup()
+
+	userID := "integration-user-add"
+	productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, true)
+	env.ProductClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
+	env.InventoryClient.On("CheckAvailabilityBatch", mock.Anything, []clients.SKUQty{{SKU: "SKU-prod1", Quantity: 2}}).Return(map[string]bool{"SKU-prod1": true}, nil)
+	env.InventoryClient.On("ReserveStock", mock.Anything, "SKU-prod1", 2).Return(nil)
+
+	w := doRequest(t, env, http.MethodPost, "/api/v1/cart/items", userID, models.AddItemRequest{
+		ProductID: "prod1",
+		Quantity:  2,
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.CartResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	cart := resp.Data
+	require.NotNil(t, cart)
+	assert.Equal(t, userID, cart.UserID)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, "prod1", cart.Items[0].ProductID)
+	assert.Equal(t, 2, cart.Items[0].Quantity)
+}
+
+func TestIntegration_UpdateAndRemoveItem(t *testing.T) {
+	env, cleanup := testutils.NewIntegrationEnv(t)
+	defer cleanup()
+
+	userID := "integration-user-update"
+	productInfo := testutils.CreateTestProductInfo("prod2", "Product 2", 5.00, true)
+	env.ProductClient.On("GetProduct", mock.Anything, "prod2").Return(productInfo, nil)
+	env.InventoryClient.On("CheckAvailabilityBatch", mock.Anything, mock.Anything).Return(map[string]bool{"SKU-prod2": true}, nil)
+	env.InventoryClient.On("ReserveStock", mock.Anything, "SKU-prod2", mock.Anything).Return(nil)
+	env.InventoryClient.On("ReleaseStock", mock.Anything, "SKU-prod2", mock.Anything).Return(nil)
+	env.InventoryClient.On("CheckAvailability", mock.Anything, "prod2", 2).Return(true, nil)
+
+	addResp := doRequest(t, env, http.MethodPost, "/api/v1/cart/items", userID, models.AddItemRequest{
+		ProductID: "prod2",
+		Quantity:  1,
+	})
+	require.Equal(t, http.StatusOK, addResp.Code)
+
+	updateResp := doRequest(t, env, http.MethodPut, "/api/v1/cart/items/prod2", userID, models.UpdateItemRequest{
+		Quantity: 3,
+	})
+	require.Equal(t, http.StatusOK, updateResp.Code)
+
+	var updatedResp models.CartResponse
+	require.NoError(t, json.Unmarshal(updateResp.Body.Bytes(), &updatedResp))
+	updated := updatedResp.Data
+	require.NotNil(t, updated)
+	require.Len(t, updated.Items, 1)
+	assert.Equal(t, 3, updated.Items[0].Quantity)
+
+	removeResp := doRequest(t, env, http.MethodDelete, "/api/v1/cart/items/prod2", userID, nil)
+	require.Equal(t, http.StatusOK, removeResp.Code)
+
+	var clearedResp models.CartResponse
+	require.NoError(t, json.Unmarshal(removeResp.Body.Bytes(), &clearedResp))
+	cleared := clearedResp.Data
+	require.NotNil(t, cleared)
+	assert.Empty(t, cleared.Items)
+}
+
+func TestIntegration_TransferCart(t *testing.T) {
+	env, cleanup := testutils.NewIntegrationEnv(t)
+	defer cleanup()
+
+	guestID := "integration-guest"
+	userID := "integration-user-transfer"
+	productInfo := testutils.CreateTestProductInfo("prod3", "Product 3", 20.00, true)
+	env.ProductClient.On("GetProduct", mock.Anything, "prod3").Return(productInfo, nil)
+	env.InventoryClient.On("CheckAvailabilityBatch", mock.Anything, mock.Anything).Return(map[string]bool{"SKU-prod3": true}, nil)
+	env.InventoryClient.On("ReserveStock", mock.Anything, "SKU-prod3", mock.Anything).Return(nil)
+	env.InventoryClient.On("CheckAvailability", mock.Anything, "prod3", 1).Return(true, nil)
+
+	addResp := doRequest(t, env, http.MethodPost, "/api/v1/cart/items", guestID, models.AddItemRequest{
+		ProductID: "prod3",
+		Quantity:  1,
+	})
+	require.Equal(t, http.StatusOK, addResp.Code)
+
+	transferResp := doRequest(t, env, http.MethodPost, "/api/v1/cart/transfer", userID, models.TransferCartRequest{
+		GuestID: guestID,
+	})
+	require.Equal(t, http.StatusOK, transferResp.Code)
+
+	var mergedResp models.CartResponse
+	require.NoError(t, json.Unmarshal(transferResp.Body.Bytes(), &mergedResp))
+	merged := mergedResp.Data
+	require.NotNil(t, merged)
+	assert.Equal(t, userID, merged.UserID)
+	require.Len(t, merged.Items, 1)
+	assert.Equal(t, "prod3", merged.Items[0].ProductID)
+}
+
+func TestIntegration_ClearCart(t *testing.T) {
+	env, cleanup := testutils.NewIntegrationEnv(t)
+	defer cleanup()
+
+	userID := "integration-user-clear"
+	productInfo := testutils.CreateTestProductInfo("prod4", "Product 4", 1.50, true)
+	env.ProductClient.On("GetProduct", mock.Anything, "prod4").Return(productInfo, nil)
+	env.InventoryClient.On("CheckAvailabilityBatch", mock.Anything, mock.Anything).Return(map[string]bool{"SKU-prod4": true}, nil)
+	env.InventoryClient.On("ReserveStock", mock.Anything, "SKU-prod4", mock.Anything).Return(nil)
+	env.InventoryClient.On("ReleaseStock", mock.Anything, "SKU-prod4", mock.Anything).Return(nil)
+
+	addResp := doRequest(t, env, http.MethodPost, "/api/v1/cart/items", userID, models.AddItemRequest{
+		ProductID: "prod4",
+		Quantity:  1,
+	})
+	require.Equal(t, http.StatusOK, addResp.Code)
+
+	clearResp := doRequest(t, env, http.MethodDelete, "/api/v1/cart", userID, nil)
+	require.Equal(t, http.StatusOK, clearResp.Code)
+
+	getResp := doRequest(t, env, http.MethodGet, "/api/v1/cart", userID, nil)
+	require.Equal(t, http.StatusOK, getResp.Code)
+
+	var resp models.CartResponse
+	require.NoError(t, json.Unmarshal(getResp.Body.Bytes(), &resp))
+	cart := resp.Data
+	require.NotNil(t, cart)
+	assert.Empty(t, cart.Items)
+}
+
+// TestIntegration_ConcurrentAddItem exercises the fencing-token path
+// (AcquireLockWithToken / SaveCartWithToken) under real concurrent writers,
+// which tests/unit/services/cart_service_test.go can only approximate with
+// mocks.MockCartRepository.
+func TestIntegration_ConcurrentAddItem(t *testing.T) {
+	env, cleanup := testutils.NewIntegrationEnv(t)
+	defer cleanup()
+
+	userID := "integration-user-concurrent"
+	productInfo := testutils.CreateTestProductInfo("prod5", "Product 5", 3.25, true)
+	env.ProductClient.On("GetProduct", mock.Anything, "prod5").Return(productInfo, nil)
+	env.InventoryClient.On("CheckAvailabilityBatch", mock.Anything, mock.Anything).Return(map[string]bool{"SKU-prod5": true}, nil)
+	env.InventoryClient.On("ReserveStock", mock.Anything, "SKU-prod5", mock.Anything).Return(nil)
+
+	const writers = 5
+	results := make(chan int, writers)
+	for i := 0; i < writers; i++ {
+		go func() {
+			w := doRequest(t, env, http.MethodPost, "/api/v1/cart/items", userID, models.AddItemRequest{
+				ProductID: "prod5",
+				Quantity:  1,
+			})
+			results <- w.Code
+		}()
+	}
+
+	for i := 0; i < writers; i++ {
+		require.Equal(t, http.StatusOK, <-results)
+	}
+
+	getResp := doRequest(t, env, http.MethodGet, "/api/v1/cart", userID, nil)
+	require.Equal(t, http.StatusOK, getResp.Code)
+
+	var resp models.CartResponse
+	require.NoError(t, json.Unmarshal(getResp.Body.Bytes(), &resp))
+	cart := resp.Data
+	require.NotNil(t, cart)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, writers, cart.Items[0].Quantity, fmt.Sprintf("expected all %d concurrent AddItem calls to be serialized through the cart lock", writers))
+}
@@ -26,6 +26,19 @@ func (m *MockCartRepository) SaveCart(ctx context.Context, cart *models.Cart) er
 	return args.Error(0)
 }
 
+func (m *MockCartRepository) GetCartWithETag(ctx context.Context, userID string) (*models.Cart, string, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).(*models.Cart), args.String(1), args.Error(2)
+}
+
+func (m *MockCartRepository) SaveCartWithETag(ctx context.Context, cart *models.Cart, etag string) error {
+	args := m.Called(ctx, cart, etag)
+	return args.Error(0)
+}
+
 func (m *MockCartRepository) DeleteCart(ctx context.Context, userID string) error {
 	args := m.Called(ctx, userID)
 	return args.Error(0)
@@ -41,13 +54,28 @@ func (m *MockCartRepository) CartExists(ctx context.Context, userID string) (boo
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockCartRepository) AcquireLock(ctx context.Context, userID string, ttl time.Duration) (bool, error) {
+func (m *MockCartRepository) AcquireLockWithToken(ctx context.Context, userID string, ttl time.Duration) (int64, bool, error) {
 	args := m.Called(ctx, userID, ttl)
-	return args.Bool(0), args.Error(1)
+	return args.Get(0).(int64), args.Bool(1), args.Error(2)
 }
 
-func (m *MockCartRepository) ReleaseLock(ctx context.Context, userID string) error {
-	args := m.Called(ctx, userID)
+func (m *MockCartRepository) ReleaseLockWithToken(ctx context.Context, userID string, token int64) error {
+	args := m.Called(ctx, userID, token)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) SaveCartWithToken(ctx context.Context, cart *models.Cart, token int64) error {
+	args := m.Called(ctx, cart, token)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) DeleteCartWithToken(ctx context.Context, userID string, token int64) error {
+	args := m.Called(ctx, userID, token)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) RefreshLock(ctx context.Context, userID string, token int64, ttl time.Duration) error {
+	args := m.Called(ctx, userID, token, ttl)
 	return args.Error(0)
 }
 
@@ -56,7 +84,48 @@ func (m *MockCartRepository) GetAllCartKeys(ctx context.Context) ([]string, erro
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockCartRepository) ListCartKeys(ctx context.Context, shard int) ([]string, error) {
+	args := m.Called(ctx, shard)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockCartRepository) ListAllCartKeys(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockCartRepository) GetCartTTL(ctx context.Context, userID string) (time.Duration, error) {
 	args := m.Called(ctx, userID)
 	return args.Get(0).(time.Duration), args.Error(1)
 }
+
+func (m *MockCartRepository) FindExpiredCartUserIDs(ctx context.Context, cutoff time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, cutoff, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockCartRepository) SaveCartWithOutbox(ctx context.Context, cart *models.Cart, records []models.OutboxRecord) error {
+	args := m.Called(ctx, cart, records)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) EnqueueOutboxRecords(ctx context.Context, records []models.OutboxRecord) error {
+	args := m.Called(ctx, records)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) ListPendingOutboxRecords(ctx context.Context, limit int) ([]models.OutboxRecord, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxRecord), args.Error(1)
+}
+
+func (m *MockCartRepository) MarkOutboxRecordPublished(ctx context.Context, recordID string) error {
+	args := m.Called(ctx, recordID)
+	return args.Error(0)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/pkg/clients"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -35,11 +36,27 @@ func (m *MockInventoryClient) CheckAvailability(ctx context.Context, productID s
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockInventoryClient) CheckAvailabilityBatch(ctx context.Context, items []clients.SKUQty) (map[string]bool, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
 func (m *MockInventoryClient) GetAvailableQuantity(ctx context.Context, productID string) (int, error) {
 	args := m.Called(ctx, productID)
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockInventoryClient) GetAvailableQuantitiesBatch(ctx context.Context, skus []string) (map[string]int, error) {
+	args := m.Called(ctx, skus)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
 func (m *MockInventoryClient) ReserveStock(ctx context.Context, productID string, quantity int) error {
 	args := m.Called(ctx, productID, quantity)
 	return args.Error(0)
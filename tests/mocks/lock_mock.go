@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aioutlet/cart-service/pkg/lock"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLocker is a mock implementation of lock.Locker
+type MockLocker struct {
+	mock.Mock
+}
+
+func (m *MockLocker) Acquire(ctx context.Context, userID, ownerID string) (*lock.Lock, error) {
+	args := m.Called(ctx, userID, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*lock.Lock), args.Error(1)
+}
+
+func (m *MockLocker) Release(ctx context.Context, heldLock *lock.Lock) {
+	m.Called(ctx, heldLock)
+}
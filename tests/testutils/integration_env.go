@@ -0,0 +1,245 @@
+//go:build integration
+
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/handlers"
+	"github.com/aioutlet/cart-service/internal/middleware"
+	"github.com/aioutlet/cart-service/internal/repository"
+	"github.com/aioutlet/cart-service/internal/services"
+	"github.com/aioutlet/cart-service/pkg/lock"
+	"github.com/aioutlet/cart-service/pkg/saga"
+	"github.com/aioutlet/cart-service/tests/mocks"
+	dapr "github.com/dapr/go-sdk/client"
+	"github.com/gin-gonic/gin"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
+)
+
+const (
+	daprStateStoreName = "statestore"
+	daprLockStoreName  = "lockstore"
+
+	// daprComponentsTemplateDir holds the component YAML files
+	// NewIntegrationEnv fills in with the Redis container's address before
+	// mounting them into the Dapr sidecar.
+	daprComponentsTemplateDir = "../integration/testdata/dapr-components"
+
+	// redisNetworkAlias is how the Dapr sidecar container addresses the
+	// Redis container over the shared Docker network startRedisContainer
+	// and startDaprSidecarContainer both join; it only needs to resolve
+	// inside that network, never from the test process itself.
+	redisNetworkAlias = "redis"
+)
+
+// IntegrationEnv is a live CartService wired against a real Redis + Dapr
+// sidecar pair, plus a *gin.Engine exposing the cart routes cmd/server
+// registers (minus auth/idempotency, which aren't this suite's concern),
+// so scenarios can be driven end-to-end over HTTP instead of calling the
+// service directly. ProductClient/InventoryClient stay test doubles: this
+// suite is about the Dapr state store and lock paths, not the product and
+// inventory services.
+type IntegrationEnv struct {
+	CartService     services.CartService
+	Router          *gin.Engine
+	ProductClient   *mocks.MockProductClient
+	InventoryClient *mocks.MockInventoryClient
+}
+
+// NewIntegrationEnv starts a Redis container and a Dapr sidecar container
+// pointed at it (state store + distributed lock components), wires a real
+// repository.CartRepository, pkg/lock.CartLocker, and pkg/saga.Orchestrator
+// against them, and registers the cart routes on a *gin.Engine.
+//
+// Requires Docker. If INTEGRATION_DAPR_GRPC_ADDRESS is set, container
+// startup is skipped entirely and that address is used instead — this is
+// the escape hatch docker-compose.test.yml gives CI environments without
+// Docker-in-Docker (see that file).
+func NewIntegrationEnv(t *testing.T) (*IntegrationEnv, func()) {
+	t.Helper()
+	ctx := context.Background()
+
+	var daprGRPCAddr string
+	cleanupContainers := func() {}
+
+	if addr := os.Getenv("INTEGRATION_DAPR_GRPC_ADDRESS"); addr != "" {
+		daprGRPCAddr = addr
+	} else {
+		net, err := network.New(ctx)
+		if err != nil {
+			t.Fatalf("failed to create Docker network: %v", err)
+		}
+
+		redisContainer := startRedisContainer(t, ctx, net.Name)
+		daprContainer, addr := startDaprSidecarContainer(t, ctx, net.Name)
+		daprGRPCAddr = addr
+		cleanupContainers = func() {
+			_ = daprContainer.Terminate(ctx)
+			_ = redisContainer.Terminate(ctx)
+			_ = net.Remove(ctx)
+		}
+	}
+
+	daprClient, err := dapr.NewClientWithAddress(daprGRPCAddr)
+	if err != nil {
+		t.Fatalf("failed to create Dapr client: %v", err)
+	}
+
+	logger := zap.NewNop()
+	cartRepo := repository.NewDaprCartRepository(daprClient, daprStateStoreName, 0, 200*time.Millisecond, logger)
+	locker := lock.NewCartLocker(daprClient, lock.Config{
+		StoreName:  daprLockStoreName,
+		LeaseTTL:   10 * time.Second,
+		MaxRetries: 3,
+		RetryDelay: 50 * time.Millisecond,
+	}, logger)
+	sagaRunner := saga.NewOrchestrator(daprClient, daprStateStoreName, logger)
+
+	productClient := &mocks.MockProductClient{}
+	inventoryClient := &mocks.MockInventoryClient{}
+
+	cfg := CreateTestConfig()
+	cartService := services.NewCartServiceWithClients(
+		cartRepo, productClient, inventoryClient, locker, sagaRunner, cfg, logger, nil,
+	)
+
+	router := gin.New()
+	cartHandler := handlers.NewCartHandler(cartService, logger)
+	cartRoutes := router.Group("/api/v1/cart")
+	cartRoutes.Use(testUserIDMiddleware(), middleware.CartContext())
+	{
+		cartRoutes.GET("", cartHandler.GetCart)
+		cartRoutes.POST("/items", cartHandler.AddItem)
+		cartRoutes.PUT("/items/:productId", cartHandler.UpdateItem)
+		cartRoutes.DELETE("/items/:productId", cartHandler.RemoveItem)
+		cartRoutes.DELETE("", cartHandler.ClearCart)
+		cartRoutes.POST("/transfer", cartHandler.TransferCart)
+	}
+
+	cleanup := func() {
+		daprClient.Close()
+		cleanupContainers()
+	}
+
+	return &IntegrationEnv{
+		CartService:     cartService,
+		Router:          router,
+		ProductClient:   productClient,
+		InventoryClient: inventoryClient,
+	}, cleanup
+}
+
+// testUserIDMiddleware stands in for middleware.AuthMiddleware: it trusts
+// an X-Test-User-Id header instead of verifying a JWT, since authentication
+// isn't this suite's concern. It must run before middleware.CartContext(),
+// which reads "userID" back out of the gin context.
+func testUserIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-Test-User-Id"))
+		c.Next()
+	}
+}
+
+func startRedisContainer(t *testing.T, ctx context.Context, networkName string) testcontainers.Container {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:          "redis:7-alpine",
+		ExposedPorts:   []string{"6379/tcp"},
+		Networks:       []string{networkName},
+		NetworkAliases: map[string][]string{networkName: {redisNetworkAlias}},
+		WaitingFor:     wait.ForLog("Ready to accept connections"),
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start Redis container: %v", err)
+	}
+
+	return container
+}
+
+func startDaprSidecarContainer(t *testing.T, ctx context.Context, networkName string) (testcontainers.Container, string) {
+	t.Helper()
+
+	componentsDir := renderDaprComponents(t, redisNetworkAlias+":6379")
+
+	req := testcontainers.ContainerRequest{
+		Image:        "daprio/daprd:1.13.0",
+		ExposedPorts: []string{"50001/tcp"},
+		Networks:     []string{networkName},
+		Cmd: []string{
+			"./daprd",
+			"--app-id", "cart-service-integration",
+			"--dapr-grpc-port", "50001",
+			"--components-path", "/components",
+		},
+		Files: []testcontainers.ContainerFile{
+			{
+				HostFilePath:      componentsDir,
+				ContainerFilePath: "/components",
+			},
+		},
+		WaitingFor: wait.ForLog("dapr initialized"),
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start Dapr sidecar container: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get Dapr container host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "50001")
+	if err != nil {
+		t.Fatalf("failed to get Dapr container port: %v", err)
+	}
+
+	return container, fmt.Sprintf("%s:%s", host, port.Port())
+}
+
+// renderDaprComponents copies daprComponentsTemplateDir into a temp dir
+// with "{{REDIS_HOST}}" substituted for redisHost, since the component
+// YAML files are checked in as templates (the Redis address is only known
+// once its container has started).
+func renderDaprComponents(t *testing.T, redisHost string) string {
+	t.Helper()
+
+	outDir := t.TempDir()
+	entries, err := os.ReadDir(daprComponentsTemplateDir)
+	if err != nil {
+		t.Fatalf("failed to read Dapr component templates: %v", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(daprComponentsTemplateDir, entry.Name()))
+		if err != nil {
+			t.Fatalf("failed to read component template %s: %v", entry.Name(), err)
+		}
+		rendered := strings.ReplaceAll(string(raw), "{{REDIS_HOST}}", redisHost)
+		if err := os.WriteFile(filepath.Join(outDir, entry.Name()), []byte(rendered), 0o644); err != nil {
+			t.Fatalf("failed to write rendered component %s: %v", entry.Name(), err)
+		}
+	}
+
+	return outDir
+}
@@ -0,0 +1,42 @@
+package cartctx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aioutlet/cart-service/internal/cartctx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBaggage_RoundTrip(t *testing.T) {
+	cc := cartctx.CartContext{
+		UserID:     "user123",
+		SessionID:  "sess-abc",
+		Currency:   "USD",
+		Experiment: "holiday-2025",
+	}
+
+	ctx, err := cartctx.WithBaggage(context.Background(), cc)
+	assert.NoError(t, err)
+
+	got := cartctx.FromContext(ctx)
+	assert.Equal(t, cc, got)
+}
+
+func TestFromContext_MissingMembersAreEmpty(t *testing.T) {
+	got := cartctx.FromContext(context.Background())
+
+	assert.Empty(t, got.UserID)
+	assert.Empty(t, got.SessionID)
+	assert.Empty(t, got.Currency)
+	assert.Empty(t, got.Experiment)
+}
+
+func TestStampSpan_DoesNotPanicWithoutRecordingSpan(t *testing.T) {
+	ctx, err := cartctx.WithBaggage(context.Background(), cartctx.CartContext{UserID: "user123"})
+	assert.NoError(t, err)
+
+	assert.NotPanics(t, func() {
+		cartctx.StampSpan(ctx)
+	})
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/events"
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/tests/mocks"
+	dapr "github.com/dapr/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// fakePublisher is a minimal events.Publisher used to simulate Dapr
+// PublishEvent successes and failures without a real sidecar.
+type fakePublisher struct {
+	err error
+}
+
+func (f *fakePublisher) PublishEvent(ctx context.Context, pubsubName, topicName string, data interface{}, opts ...dapr.PublishEventOption) error {
+	return f.err
+}
+
+func TestOutboxDispatcher_Dispatch_MarksPublishedOnSuccess(t *testing.T) {
+	repo := new(mocks.MockCartRepository)
+	record := models.OutboxRecord{
+		ID:     "rec-1",
+		Type:   models.OutboxEventCartItemAdded,
+		UserID: "user123",
+	}
+	repo.On("ListPendingOutboxRecords", mock.Anything, 10).Return([]models.OutboxRecord{record}, nil)
+	repo.On("MarkOutboxRecordPublished", mock.Anything, "rec-1").Return(nil)
+
+	dispatcher := events.NewOutboxDispatcher(repo, &fakePublisher{}, "pubsub", "cart-events", time.Minute, 10, zap.NewNop())
+
+	err := dispatcher.Dispatch(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxDispatcher_Dispatch_PublishFailureLeavesRecordForRetry(t *testing.T) {
+	repo := new(mocks.MockCartRepository)
+	record := models.OutboxRecord{
+		ID:     "rec-1",
+		Type:   models.OutboxEventCartItemAdded,
+		UserID: "user123",
+	}
+	repo.On("ListPendingOutboxRecords", mock.Anything, 10).Return([]models.OutboxRecord{record}, nil)
+
+	dispatcher := events.NewOutboxDispatcher(repo, &fakePublisher{err: errors.New("sidecar unreachable")}, "pubsub", "cart-events", time.Minute, 10, zap.NewNop())
+
+	err := dispatcher.Dispatch(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "MarkOutboxRecordPublished", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
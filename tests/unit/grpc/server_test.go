@@ -0,0 +1,213 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	cartgrpc "github.com/aioutlet/cart-service/internal/grpc"
+	"github.com/aioutlet/cart-service/internal/grpc/cartpb"
+	"github.com/aioutlet/cart-service/internal/services"
+	"github.com/aioutlet/cart-service/pkg/clients"
+	"github.com/aioutlet/cart-service/pkg/lock"
+	"github.com/aioutlet/cart-service/pkg/saga"
+	"github.com/aioutlet/cart-service/tests/mocks"
+	"github.com/aioutlet/cart-service/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1024 * 1024
+
+// fakeSagaRunner runs a saga's steps in order and compensates completed
+// steps in reverse on failure, like saga.Orchestrator, but without touching
+// a Dapr state store.
+type fakeSagaRunner struct{}
+
+func (fakeSagaRunner) Run(ctx context.Context, sagaID string, steps []saga.Step) error {
+	completed := make([]saga.Step, 0, len(steps))
+	for _, step := range steps {
+		if err := step.Do(ctx); err != nil {
+			for i := len(completed) - 1; i >= 0; i-- {
+				if completed[i].Compensate != nil {
+					_ = completed[i].Compensate(ctx)
+				}
+			}
+			return err
+		}
+		completed = append(completed, step)
+	}
+	return nil
+}
+
+func dialer(grpcSrv *grpc.Server) func(context.Context, string) (net.Conn, error) {
+	listener := bufconn.Listen(bufSize)
+	go func() {
+		_ = grpcSrv.Serve(listener)
+	}()
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.Dial()
+	}
+}
+
+func TestCartServer_AddItem(t *testing.T) {
+	mockRepo := &mocks.MockCartRepository{}
+	mockProductClient := &mocks.MockProductClient{}
+	mockInventoryClient := &mocks.MockInventoryClient{}
+	mockLocker := &mocks.MockLocker{}
+	cfg := testutils.CreateTestConfig()
+
+	cartService := services.NewCartServiceWithClients(mockRepo, mockProductClient, mockInventoryClient, mockLocker, fakeSagaRunner{}, cfg, zap.NewNop(), nil)
+
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, true)
+
+	mockLocker.On("Acquire", mock.Anything, "user123", mock.Anything).Return(&lock.Lock{}, nil)
+	mockLocker.On("Release", mock.Anything, mock.Anything).Return()
+	mockProductClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
+	mockInventoryClient.On("CheckAvailabilityBatch", mock.Anything, []clients.SKUQty{{SKU: "SKU-prod1", Quantity: 2}}).Return(map[string]bool{"SKU-prod1": true}, nil)
+	mockInventoryClient.On("ReserveStock", mock.Anything, "SKU-prod1", 2).Return(nil)
+	mockRepo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
+	mockRepo.On("SaveCartWithETag", mock.Anything, mock.AnythingOfType("*models.Cart"), "etag-1").Return(nil)
+	mockRepo.On("AcquireLockWithToken", mock.Anything, "user123", mock.Anything).Return(int64(0), false, nil)
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
+
+	cartServer := cartgrpc.NewCartServer(cartService, zap.NewNop())
+	grpcSrv := grpc.NewServer()
+	cartgrpc.RegisterServer(grpcSrv, cartServer)
+	defer grpcSrv.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(dialer(grpcSrv)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	client := cartpb.NewCartServiceClient(conn)
+
+	resp, err := client.AddItem(ctx, &cartpb.AddItemRequest{
+		UserId:    "user123",
+		ProductId: "prod1",
+		Quantity:  2,
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.Cart)
+	assert.Equal(t, "user123", resp.Cart.UserId)
+
+	mockRepo.AssertExpectations(t)
+	mockProductClient.AssertExpectations(t)
+	mockInventoryClient.AssertExpectations(t)
+	mockLocker.AssertExpectations(t)
+}
+
+func TestCartServer_RemoveItemAndClearCart(t *testing.T) {
+	mockRepo := &mocks.MockCartRepository{}
+	mockProductClient := &mocks.MockProductClient{}
+	mockInventoryClient := &mocks.MockInventoryClient{}
+	mockLocker := &mocks.MockLocker{}
+	cfg := testutils.CreateTestConfig()
+
+	cartService := services.NewCartServiceWithClients(mockRepo, mockProductClient, mockInventoryClient, mockLocker, fakeSagaRunner{}, cfg, zap.NewNop(), nil)
+
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	item := testutils.CreateTestCartItem("prod1", "Product 1", 10.99, 2)
+	cart.AddItem(item, 10, 5)
+
+	mockLocker.On("Acquire", mock.Anything, "user123", mock.Anything).Return(&lock.Lock{}, nil)
+	mockLocker.On("Release", mock.Anything, mock.Anything).Return()
+	mockRepo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
+	mockRepo.On("SaveCartWithETag", mock.Anything, mock.AnythingOfType("*models.Cart"), "etag-1").Return(nil)
+	mockRepo.On("DeleteCart", mock.Anything, "user123").Return(nil)
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
+
+	cartServer := cartgrpc.NewCartServer(cartService, zap.NewNop())
+	grpcSrv := grpc.NewServer()
+	cartgrpc.RegisterServer(grpcSrv, cartServer)
+	defer grpcSrv.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(dialer(grpcSrv)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	client := cartpb.NewCartServiceClient(conn)
+
+	removeResp, err := client.RemoveItem(ctx, &cartpb.RemoveItemRequest{UserId: "user123", ProductId: "prod1"})
+	assert.NoError(t, err)
+	assert.NotNil(t, removeResp.Cart)
+	assert.Empty(t, removeResp.Cart.Items)
+
+	_, err = client.ClearCart(ctx, &cartpb.ClearCartRequest{UserId: "user123"})
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLocker.AssertExpectations(t)
+}
+
+func TestCartServer_TransferCart(t *testing.T) {
+	mockRepo := &mocks.MockCartRepository{}
+	mockProductClient := &mocks.MockProductClient{}
+	mockInventoryClient := &mocks.MockInventoryClient{}
+	mockLocker := &mocks.MockLocker{}
+	cfg := testutils.CreateTestConfig()
+
+	cartService := services.NewCartServiceWithClients(mockRepo, mockProductClient, mockInventoryClient, mockLocker, fakeSagaRunner{}, cfg, zap.NewNop(), nil)
+
+	sourceCart := testutils.CreateTestCart("guest123", time.Hour)
+	item := testutils.CreateTestCartItem("prod1", "Product 1", 10.99, 2)
+	sourceCart.AddItem(item, 10, 5)
+	targetCart := testutils.CreateTestCart("user123", time.Hour)
+
+	mockLocker.On("Acquire", mock.Anything, "guest123", mock.Anything).Return(&lock.Lock{}, nil)
+	mockLocker.On("Acquire", mock.Anything, "user123", mock.Anything).Return(&lock.Lock{}, nil)
+	mockLocker.On("Release", mock.Anything, mock.Anything).Return()
+	mockRepo.On("GetCart", mock.Anything, "guest123").Return(sourceCart, nil)
+	mockRepo.On("GetCart", mock.Anything, "user123").Return(targetCart, nil)
+	mockRepo.On("SaveCart", mock.Anything, mock.AnythingOfType("*models.Cart")).Return(nil)
+	mockRepo.On("DeleteCart", mock.Anything, "guest123").Return(nil)
+	mockRepo.On("AcquireLockWithToken", mock.Anything, "guest123", mock.Anything).Return(int64(0), false, nil)
+	mockRepo.On("AcquireLockWithToken", mock.Anything, "user123", mock.Anything).Return(int64(0), false, nil)
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
+	mockInventoryClient.On("CheckAvailability", mock.Anything, "prod1", 2).Return(true, nil)
+
+	cartServer := cartgrpc.NewCartServer(cartService, zap.NewNop())
+	grpcSrv := grpc.NewServer()
+	cartgrpc.RegisterServer(grpcSrv, cartServer)
+	defer grpcSrv.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(dialer(grpcSrv)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	client := cartpb.NewCartServiceClient(conn)
+
+	resp, err := client.TransferCart(ctx, &cartpb.TransferCartRequest{FromUserId: "guest123", ToUserId: "user123"})
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.Cart)
+	assert.Equal(t, "user123", resp.Cart.UserId)
+	assert.True(t, len(resp.Cart.Items) > 0)
+
+	mockRepo.AssertExpectations(t)
+	mockLocker.AssertExpectations(t)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/aioutlet/cart-service/internal/models"
 	"github.com/aioutlet/cart-service/tests/testutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewCart(t *testing.T) {
@@ -275,6 +276,68 @@ func TestCart_RemoveItem(t *testing.T) {
 	}
 }
 
+// TestCart_RemoveItem_BundleCascade covers bundle items: removing one child
+// of a bundle (same BundleParentID) must remove every other child with it,
+// and leave unrelated items untouched.
+func TestCart_RemoveItem_BundleCascade(t *testing.T) {
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	bundleItem1 := testutils.CreateTestCartItem("prod1", "Product 1", 10.99, 1)
+	bundleItem1.BundleParentID = "bundle-1"
+	bundleItem2 := testutils.CreateTestCartItem("prod2", "Product 2", 5.00, 2)
+	bundleItem2.BundleParentID = "bundle-1"
+	other := testutils.CreateTestCartItem("prod3", "Product 3", 20.00, 1)
+
+	cart.AddItem(bundleItem1, 10, 5)
+	cart.AddItem(bundleItem2, 10, 5)
+	cart.AddItem(other, 10, 5)
+
+	err := cart.RemoveItem("prod1")
+
+	assert.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, "prod3", cart.Items[0].ProductID)
+}
+
+// TestCart_UpdateItemQuantity_BundleCascadeOnZero covers setting a bundle
+// item's quantity to 0 via UpdateItemQuantity: it must remove the whole
+// bundle, same as RemoveItem.
+func TestCart_UpdateItemQuantity_BundleCascadeOnZero(t *testing.T) {
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	bundleItem1 := testutils.CreateTestCartItem("prod1", "Product 1", 10.99, 1)
+	bundleItem1.BundleParentID = "bundle-1"
+	bundleItem2 := testutils.CreateTestCartItem("prod2", "Product 2", 5.00, 2)
+	bundleItem2.BundleParentID = "bundle-1"
+
+	cart.AddItem(bundleItem1, 10, 5)
+	cart.AddItem(bundleItem2, 10, 5)
+
+	err := cart.UpdateItemQuantity("prod1", 0, 5)
+
+	assert.NoError(t, err)
+	assert.Empty(t, cart.Items)
+}
+
+// TestCart_UpdateItemQuantity_BundleNonZeroRejected covers changing a bundle
+// item's quantity to a non-zero value via UpdateItemQuantity: it must be
+// rejected rather than leave sibling bundle items at their old quantity.
+func TestCart_UpdateItemQuantity_BundleNonZeroRejected(t *testing.T) {
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	bundleItem1 := testutils.CreateTestCartItem("prod1", "Product 1", 10.99, 1)
+	bundleItem1.BundleParentID = "bundle-1"
+	bundleItem2 := testutils.CreateTestCartItem("prod2", "Product 2", 5.00, 2)
+	bundleItem2.BundleParentID = "bundle-1"
+
+	cart.AddItem(bundleItem1, 10, 5)
+	cart.AddItem(bundleItem2, 10, 5)
+
+	err := cart.UpdateItemQuantity("prod1", 3, 5)
+
+	assert.ErrorIs(t, err, models.ErrBundleItemQuantityImmutable)
+	require.Len(t, cart.Items, 2)
+	assert.Equal(t, 1, cart.Items[0].Quantity)
+	assert.Equal(t, 2, cart.Items[1].Quantity)
+}
+
 func TestCart_Clear(t *testing.T) {
 	cart := testutils.CreateTestCart("user123", time.Hour)
 	item1 := testutils.CreateTestCartItem("prod1", "Product 1", 10.99, 2)
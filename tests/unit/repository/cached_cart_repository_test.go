@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/repository"
+	"github.com/aioutlet/cart-service/tests/mocks"
+	"github.com/aioutlet/cart-service/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCachedCartRepository_GetCart_CachesBetweenCalls covers the read-through
+// behavior a checkout flow relies on: GetCart only reaches the wrapped
+// repository once, even when called several times in a row (e.g.
+// ValidateCart followed by GetCartSummary).
+func TestCachedCartRepository_GetCart_CachesBetweenCalls(t *testing.T) {
+	inner := &mocks.MockCartRepository{}
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	inner.On("GetCart", mock.Anything, "user123").Return(cart, nil).Once()
+
+	cached := repository.NewCachedCartRepository(inner, time.Minute)
+
+	first, err := cached.GetCart(context.Background(), "user123")
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", first.UserID)
+
+	second, err := cached.GetCart(context.Background(), "user123")
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", second.UserID)
+
+	inner.AssertExpectations(t)
+}
+
+// TestCachedCartRepository_SaveCart_InvalidatesEntry covers the other half
+// of the contract: a write must be visible to the very next GetCart, so
+// SaveCart has to drop the cached entry rather than wait out the TTL.
+func TestCachedCartRepository_SaveCart_InvalidatesEntry(t *testing.T) {
+	inner := &mocks.MockCartRepository{}
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	inner.On("GetCart", mock.Anything, "user123").Return(cart, nil).Twice()
+	inner.On("SaveCart", mock.Anything, cart).Return(nil).Once()
+
+	cached := repository.NewCachedCartRepository(inner, time.Minute)
+
+	_, err := cached.GetCart(context.Background(), "user123")
+	assert.NoError(t, err)
+
+	assert.NoError(t, cached.SaveCart(context.Background(), cart))
+
+	_, err = cached.GetCart(context.Background(), "user123")
+	assert.NoError(t, err)
+
+	inner.AssertExpectations(t)
+}
+
+// TestCachedCartRepository_Invalidate covers the externally-exposed
+// Invalidate call for callers outside the read/write paths this type
+// already hooks.
+func TestCachedCartRepository_Invalidate(t *testing.T) {
+	inner := &mocks.MockCartRepository{}
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	inner.On("GetCart", mock.Anything, "user123").Return(cart, nil).Twice()
+
+	cached := repository.NewCachedCartRepository(inner, time.Minute)
+
+	_, err := cached.GetCart(context.Background(), "user123")
+	assert.NoError(t, err)
+
+	cached.Invalidate("user123")
+
+	_, err = cached.GetCart(context.Background(), "user123")
+	assert.NoError(t, err)
+
+	inner.AssertExpectations(t)
+}
+
+// TestCachedCartRepository_ZeroTTLDisablesCache covers the documented
+// fallback: a non-positive ttl means every GetCart reaches inner.
+func TestCachedCartRepository_ZeroTTLDisablesCache(t *testing.T) {
+	inner := &mocks.MockCartRepository{}
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	inner.On("GetCart", mock.Anything, "user123").Return(cart, nil).Twice()
+
+	cached := repository.NewCachedCartRepository(inner, 0)
+
+	_, err := cached.GetCart(context.Background(), "user123")
+	assert.NoError(t, err)
+	_, err = cached.GetCart(context.Background(), "user123")
+	assert.NoError(t, err)
+
+	inner.AssertExpectations(t)
+}
+
+// benchmarkCartRepo is a minimal CartRepository stand-in for the
+// benchmarks below: it counts GetCart calls instead of asserting on them,
+// since testify's mock.Mock call-matching overhead would dominate the
+// measurement.
+type benchmarkCartRepo struct {
+	mocks.MockCartRepository
+	getCartCalls int
+}
+
+func (r *benchmarkCartRepo) GetCart(ctx context.Context, userID string) (*models.Cart, error) {
+	r.getCartCalls++
+	return testutils.CreateTestCart(userID, time.Hour), nil
+}
+
+// BenchmarkCheckoutFlow_Uncached simulates a typical checkout's read
+// pattern — GetCart called repeatedly against the same user (e.g. by
+// ValidateCart and then GetCartSummary) — straight against the wrapped
+// repository, with no cache in front of it.
+func BenchmarkCheckoutFlow_Uncached(b *testing.B) {
+	repo := &benchmarkCartRepo{}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		repo.GetCart(ctx, "user123")
+		repo.GetCart(ctx, "user123")
+		repo.GetCart(ctx, "user123")
+	}
+}
+
+// BenchmarkCheckoutFlow_Cached runs the same read pattern through
+// CachedCartRepository, demonstrating the Redis round-trip reduction: only
+// the first GetCart per b.N iteration reaches the wrapped repository.
+func BenchmarkCheckoutFlow_Cached(b *testing.B) {
+	repo := &benchmarkCartRepo{}
+	cached := repository.NewCachedCartRepository(repo, time.Minute)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cached.GetCart(ctx, "user123")
+		cached.GetCart(ctx, "user123")
+		cached.GetCart(ctx, "user123")
+		cached.Invalidate("user123") // next iteration's checkout is a new request
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/aioutlet/cart-service/internal/models"
 	"github.com/aioutlet/cart-service/internal/services"
+	"github.com/aioutlet/cart-service/pkg/clients"
+	"github.com/aioutlet/cart-service/pkg/lock"
+	"github.com/aioutlet/cart-service/pkg/saga"
 	"github.com/aioutlet/cart-service/tests/mocks"
 	"github.com/aioutlet/cart-service/tests/testutils"
 	"github.com/stretchr/testify/assert"
@@ -15,10 +18,33 @@ import (
 	"go.uber.org/zap"
 )
 
-func setupCartService() (services.CartService, *mocks.MockCartRepository, *mocks.MockProductClient, *mocks.MockInventoryClient) {
+// fakeSagaRunner runs a saga's steps in order and compensates completed
+// steps in reverse on failure, like saga.Orchestrator, but without touching
+// a Dapr state store — the saga log itself is exercised in production, not
+// re-verified by these cart service unit tests.
+type fakeSagaRunner struct{}
+
+func (fakeSagaRunner) Run(ctx context.Context, sagaID string, steps []saga.Step) error {
+	completed := make([]saga.Step, 0, len(steps))
+	for _, step := range steps {
+		if err := step.Do(ctx); err != nil {
+			for i := len(completed) - 1; i >= 0; i-- {
+				if completed[i].Compensate != nil {
+					_ = completed[i].Compensate(ctx)
+				}
+			}
+			return err
+		}
+		completed = append(completed, step)
+	}
+	return nil
+}
+
+func setupCartService() (services.CartService, *mocks.MockCartRepository, *mocks.MockProductClient, *mocks.MockInventoryClient, *mocks.MockLocker) {
 	mockRepo := &mocks.MockCartRepository{}
 	mockProductClient := &mocks.MockProductClient{}
 	mockInventoryClient := &mocks.MockInventoryClient{}
+	mockLocker := &mocks.MockLocker{}
 	config := testutils.CreateTestConfig()
 	logger := zap.NewNop()
 
@@ -27,11 +53,29 @@ func setupCartService() (services.CartService, *mocks.MockCartRepository, *mocks
 		mockRepo,
 		mockProductClient,
 		mockInventoryClient,
+		mockLocker,
+		fakeSagaRunner{},
 		config,
 		logger,
+		nil,
 	)
 
-	return cartService, mockRepo, mockProductClient, mockInventoryClient
+	return cartService, mockRepo, mockProductClient, mockInventoryClient, mockLocker
+}
+
+// expectLock sets up a held-lock round trip for userID: any owner ID is
+// accepted since it's derived from the request's correlation ID.
+func expectLock(locker *mocks.MockLocker, userID string) {
+	locker.On("Acquire", mock.Anything, userID, mock.Anything).Return(&lock.Lock{}, nil)
+	locker.On("Release", mock.Anything, mock.Anything).Return()
+}
+
+// expectRepoToken sets up a round trip for the repository-level fencing
+// token lock AddItem takes out alongside the Dapr lock (see
+// CartService.AddItem and CartRepository.AcquireLockWithToken).
+func expectRepoToken(repo *mocks.MockCartRepository, userID string, token int64) {
+	repo.On("AcquireLockWithToken", mock.Anything, userID, mock.Anything).Return(token, true, nil)
+	repo.On("ReleaseLockWithToken", mock.Anything, userID, token).Return(nil)
 }
 
 func TestCartService_GetCart(t *testing.T) {
@@ -66,7 +110,7 @@ func TestCartService_GetCart(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cartService, mockRepo, _, _ := setupCartService()
+			cartService, mockRepo, _, _, _ := setupCartService()
 			tt.setupMocks(mockRepo)
 
 			cart, err := cartService.GetCart(context.Background(), tt.userID)
@@ -94,7 +138,7 @@ func TestCartService_AddItem(t *testing.T) {
 		name          string
 		userID        string
 		request       models.AddItemRequest
-		setupMocks    func(*mocks.MockCartRepository, *mocks.MockProductClient, *mocks.MockInventoryClient)
+		setupMocks    func(*mocks.MockCartRepository, *mocks.MockLocker, *mocks.MockProductClient, *mocks.MockInventoryClient)
 		expectedError error
 	}{
 		{
@@ -104,22 +148,24 @@ func TestCartService_AddItem(t *testing.T) {
 				ProductID: "prod1",
 				Quantity:  2,
 			},
-			setupMocks: func(repo *mocks.MockCartRepository, productClient *mocks.MockProductClient, inventoryClient *mocks.MockInventoryClient) {
-				// Mock lock operations
-				repo.On("AcquireLock", mock.Anything, "user123", 30*time.Second).Return(true, nil)
-				repo.On("ReleaseLock", mock.Anything, "user123").Return(nil)
+			setupMocks: func(repo *mocks.MockCartRepository, locker *mocks.MockLocker, productClient *mocks.MockProductClient, inventoryClient *mocks.MockInventoryClient) {
+				expectLock(locker, "user123")
+				expectRepoToken(repo, "user123", 1)
+				repo.On("RefreshLock", mock.Anything, "user123", int64(1), mock.Anything).Return(nil)
 
 				// Mock product service
 				productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, true)
 				productClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
 
 				// Mock inventory service
-				inventoryClient.On("CheckAvailability", mock.Anything, "prod1", 2).Return(true, nil)
+				inventoryClient.On("CheckAvailabilityBatch", mock.Anything, []clients.SKUQty{{SKU: "SKU-prod1", Quantity: 2}}).Return(map[string]bool{"SKU-prod1": true}, nil)
+				inventoryClient.On("ReserveStock", mock.Anything, "SKU-prod1", 2).Return(nil)
 
 				// Mock repository operations
 				cart := testutils.CreateTestCart("user123", time.Hour)
-				repo.On("GetCart", mock.Anything, "user123").Return(cart, nil)
-				repo.On("SaveCart", mock.Anything, mock.AnythingOfType("*models.Cart")).Return(nil)
+				repo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
+				repo.On("SaveCartWithToken", mock.Anything, mock.AnythingOfType("*models.Cart"), int64(1)).Return(nil)
+				repo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -130,9 +176,9 @@ func TestCartService_AddItem(t *testing.T) {
 				ProductID: "nonexistent",
 				Quantity:  2,
 			},
-			setupMocks: func(repo *mocks.MockCartRepository, productClient *mocks.MockProductClient, inventoryClient *mocks.MockInventoryClient) {
-				repo.On("AcquireLock", mock.Anything, "user123", 30*time.Second).Return(true, nil)
-				repo.On("ReleaseLock", mock.Anything, "user123").Return(nil)
+			setupMocks: func(repo *mocks.MockCartRepository, locker *mocks.MockLocker, productClient *mocks.MockProductClient, inventoryClient *mocks.MockInventoryClient) {
+				expectLock(locker, "user123")
+				expectRepoToken(repo, "user123", 1)
 				productClient.On("GetProduct", mock.Anything, "nonexistent").Return(nil, models.ErrProductNotFound)
 			},
 			expectedError: models.ErrProductNotFound,
@@ -144,13 +190,13 @@ func TestCartService_AddItem(t *testing.T) {
 				ProductID: "prod1",
 				Quantity:  10,
 			},
-			setupMocks: func(repo *mocks.MockCartRepository, productClient *mocks.MockProductClient, inventoryClient *mocks.MockInventoryClient) {
-				repo.On("AcquireLock", mock.Anything, "user123", 30*time.Second).Return(true, nil)
-				repo.On("ReleaseLock", mock.Anything, "user123").Return(nil)
+			setupMocks: func(repo *mocks.MockCartRepository, locker *mocks.MockLocker, productClient *mocks.MockProductClient, inventoryClient *mocks.MockInventoryClient) {
+				expectLock(locker, "user123")
+				expectRepoToken(repo, "user123", 1)
 
 				productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, true)
 				productClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
-				inventoryClient.On("CheckAvailability", mock.Anything, "prod1", 10).Return(false, nil)
+				inventoryClient.On("CheckAvailabilityBatch", mock.Anything, []clients.SKUQty{{SKU: "SKU-prod1", Quantity: 10}}).Return(map[string]bool{"SKU-prod1": false}, nil)
 			},
 			expectedError: models.ErrInsufficientStock,
 		},
@@ -161,9 +207,9 @@ func TestCartService_AddItem(t *testing.T) {
 				ProductID: "prod1",
 				Quantity:  2,
 			},
-			setupMocks: func(repo *mocks.MockCartRepository, productClient *mocks.MockProductClient, inventoryClient *mocks.MockInventoryClient) {
-				repo.On("AcquireLock", mock.Anything, "user123", 30*time.Second).Return(true, nil)
-				repo.On("ReleaseLock", mock.Anything, "user123").Return(nil)
+			setupMocks: func(repo *mocks.MockCartRepository, locker *mocks.MockLocker, productClient *mocks.MockProductClient, inventoryClient *mocks.MockInventoryClient) {
+				expectLock(locker, "user123")
+				expectRepoToken(repo, "user123", 1)
 
 				productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, false)
 				productClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
@@ -174,8 +220,8 @@ func TestCartService_AddItem(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cartService, mockRepo, mockProductClient, mockInventoryClient := setupCartService()
-			tt.setupMocks(mockRepo, mockProductClient, mockInventoryClient)
+			cartService, mockRepo, mockProductClient, mockInventoryClient, mockLocker := setupCartService()
+			tt.setupMocks(mockRepo, mockLocker, mockProductClient, mockInventoryClient)
 
 			cart, err := cartService.AddItem(context.Background(), tt.userID, tt.request)
 
@@ -194,17 +240,162 @@ func TestCartService_AddItem(t *testing.T) {
 			mockRepo.AssertExpectations(t)
 			mockProductClient.AssertExpectations(t)
 			mockInventoryClient.AssertExpectations(t)
+			mockLocker.AssertExpectations(t)
 		})
 	}
 }
 
+// TestCartService_AddItem_EnqueuesOutboxEvent asserts that a successful
+// AddItem enqueues exactly one CartItemAdded outbox record for the acting
+// user alongside the cart save, so the event is published at-least-once via
+// the outbox dispatcher even if the mutation and the publish happen at
+// different times.
+func TestCartService_AddItem_EnqueuesOutboxEvent(t *testing.T) {
+	cartService, mockRepo, mockProductClient, mockInventoryClient, mockLocker := setupCartService()
+
+	expectLock(mockLocker, "user123")
+	expectRepoToken(mockRepo, "user123", 1)
+	mockRepo.On("RefreshLock", mock.Anything, "user123", int64(1), mock.Anything).Return(nil)
+
+	productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, true)
+	mockProductClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
+	mockInventoryClient.On("CheckAvailabilityBatch", mock.Anything, []clients.SKUQty{{SKU: "SKU-prod1", Quantity: 1}}).Return(map[string]bool{"SKU-prod1": true}, nil)
+	mockInventoryClient.On("ReserveStock", mock.Anything, "SKU-prod1", 1).Return(nil)
+
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	mockRepo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
+	mockRepo.On("SaveCartWithToken", mock.Anything, mock.AnythingOfType("*models.Cart"), int64(1)).Return(nil)
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.MatchedBy(func(records []models.OutboxRecord) bool {
+		return len(records) == 1 &&
+			records[0].Type == models.OutboxEventCartItemAdded &&
+			records[0].UserID == "user123"
+	})).Return(nil)
+
+	request := models.AddItemRequest{ProductID: "prod1", Quantity: 1}
+	_, err := cartService.AddItem(context.Background(), "user123", request)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCartService_AddItem_TokenMonotonicity exercises the repository-level
+// fencing token AddItem plumbs alongside the Dapr lock (see
+// CartRepository.AcquireLockWithToken): each call forwards whatever token
+// the repository issued to the matching SaveCartWithToken call, so two
+// back-to-back AddItem calls for the same user persist under two distinct,
+// increasing tokens rather than reusing the first.
+func TestCartService_AddItem_TokenMonotonicity(t *testing.T) {
+	cartService, mockRepo, mockProductClient, mockInventoryClient, mockLocker := setupCartService()
+
+	expectLock(mockLocker, "user123")
+	productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, true)
+	mockProductClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
+	mockInventoryClient.On("CheckAvailabilityBatch", mock.Anything, []clients.SKUQty{{SKU: "SKU-prod1", Quantity: 1}}).Return(map[string]bool{"SKU-prod1": true}, nil)
+	mockInventoryClient.On("ReserveStock", mock.Anything, "SKU-prod1", 1).Return(nil)
+
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	mockRepo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
+
+	mockRepo.On("AcquireLockWithToken", mock.Anything, "user123", mock.Anything).Return(int64(1), true, nil).Once()
+	mockRepo.On("ReleaseLockWithToken", mock.Anything, "user123", int64(1)).Return(nil).Once()
+	mockRepo.On("RefreshLock", mock.Anything, "user123", int64(1), mock.Anything).Return(nil).Once()
+	mockRepo.On("SaveCartWithToken", mock.Anything, mock.AnythingOfType("*models.Cart"), int64(1)).Return(nil).Once()
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil).Once()
+
+	mockRepo.On("AcquireLockWithToken", mock.Anything, "user123", mock.Anything).Return(int64(2), true, nil).Once()
+	mockRepo.On("ReleaseLockWithToken", mock.Anything, "user123", int64(2)).Return(nil).Once()
+	mockRepo.On("RefreshLock", mock.Anything, "user123", int64(2), mock.Anything).Return(nil).Once()
+	mockRepo.On("SaveCartWithToken", mock.Anything, mock.AnythingOfType("*models.Cart"), int64(2)).Return(nil).Once()
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil).Once()
+
+	request := models.AddItemRequest{ProductID: "prod1", Quantity: 1}
+
+	_, err := cartService.AddItem(context.Background(), "user123", request)
+	assert.NoError(t, err)
+
+	_, err = cartService.AddItem(context.Background(), "user123", request)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCartService_AddItem_StaleLockTokenRejected covers the expired-holder
+// scenario a fencing token exists for: this holder's Dapr lock lease
+// expired mid-request, another process acquired the lock next and has
+// already issued a newer token, so SaveCartWithToken rejects the stale
+// write with models.ErrLockTokenMismatch instead of silently clobbering
+// the newer holder's cart.
+func TestCartService_AddItem_StaleLockTokenRejected(t *testing.T) {
+	cartService, mockRepo, mockProductClient, mockInventoryClient, mockLocker := setupCartService()
+
+	expectLock(mockLocker, "user123")
+	expectRepoToken(mockRepo, "user123", 1)
+	mockRepo.On("RefreshLock", mock.Anything, "user123", int64(1), mock.Anything).Return(nil)
+
+	productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, true)
+	mockProductClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
+	mockInventoryClient.On("CheckAvailabilityBatch", mock.Anything, []clients.SKUQty{{SKU: "SKU-prod1", Quantity: 1}}).Return(map[string]bool{"SKU-prod1": true}, nil)
+	mockInventoryClient.On("ReserveStock", mock.Anything, "SKU-prod1", 1).Return(nil)
+	mockInventoryClient.On("ReleaseStock", mock.Anything, "SKU-prod1", 1).Return(nil)
+
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	mockRepo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
+	mockRepo.On("SaveCartWithToken", mock.Anything, mock.AnythingOfType("*models.Cart"), int64(1)).
+		Return(models.ErrLockTokenMismatch)
+
+	request := models.AddItemRequest{ProductID: "prod1", Quantity: 1}
+	result, err := cartService.AddItem(context.Background(), "user123", request)
+
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, models.ErrLockTokenMismatch)
+	assert.Nil(t, result)
+
+	mockRepo.AssertExpectations(t)
+	mockInventoryClient.AssertExpectations(t)
+}
+
+// TestCartService_AddItem_ReleaseAfterExpiredLockIsNoOp covers the
+// release-after-TTL scenario: by the time AddItem finishes and releases its
+// repository-level lock token, the lock has already expired (and possibly
+// been reacquired by someone else). ReleaseLockWithToken's compare-and-
+// delete then matches nothing and returns nil rather than an error (see
+// CartRepository.ReleaseLockWithToken), so AddItem's deferred release
+// doesn't turn an otherwise-successful add into a failure.
+func TestCartService_AddItem_ReleaseAfterExpiredLockIsNoOp(t *testing.T) {
+	cartService, mockRepo, mockProductClient, mockInventoryClient, mockLocker := setupCartService()
+
+	expectLock(mockLocker, "user123")
+	productInfo := testutils.CreateTestProductInfo("prod1", "Product 1", 10.99, true)
+	mockProductClient.On("GetProduct", mock.Anything, "prod1").Return(productInfo, nil)
+	mockInventoryClient.On("CheckAvailabilityBatch", mock.Anything, []clients.SKUQty{{SKU: "SKU-prod1", Quantity: 1}}).Return(map[string]bool{"SKU-prod1": true}, nil)
+	mockInventoryClient.On("ReserveStock", mock.Anything, "SKU-prod1", 1).Return(nil)
+
+	cart := testutils.CreateTestCart("user123", time.Hour)
+	mockRepo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
+	mockRepo.On("AcquireLockWithToken", mock.Anything, "user123", mock.Anything).Return(int64(1), true, nil)
+	mockRepo.On("RefreshLock", mock.Anything, "user123", int64(1), mock.Anything).Return(nil)
+	mockRepo.On("SaveCartWithToken", mock.Anything, mock.AnythingOfType("*models.Cart"), int64(1)).Return(nil)
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
+	// The lock already expired by release time, so the compare-and-delete
+	// matches nothing; ReleaseLockWithToken still returns nil.
+	mockRepo.On("ReleaseLockWithToken", mock.Anything, "user123", int64(1)).Return(nil)
+
+	request := models.AddItemRequest{ProductID: "prod1", Quantity: 1}
+	result, err := cartService.AddItem(context.Background(), "user123", request)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestCartService_UpdateItem(t *testing.T) {
 	tests := []struct {
 		name          string
 		userID        string
 		productID     string
 		request       models.UpdateItemRequest
-		setupMocks    func(*mocks.MockCartRepository, *mocks.MockInventoryClient)
+		setupMocks    func(*mocks.MockCartRepository, *mocks.MockLocker, *mocks.MockInventoryClient)
 		expectedError error
 	}{
 		{
@@ -214,17 +405,17 @@ func TestCartService_UpdateItem(t *testing.T) {
 			request: models.UpdateItemRequest{
 				Quantity: 3,
 			},
-			setupMocks: func(repo *mocks.MockCartRepository, inventoryClient *mocks.MockInventoryClient) {
-				repo.On("AcquireLock", mock.Anything, "user123", 30*time.Second).Return(true, nil)
-				repo.On("ReleaseLock", mock.Anything, "user123").Return(nil)
+			setupMocks: func(repo *mocks.MockCartRepository, locker *mocks.MockLocker, inventoryClient *mocks.MockInventoryClient) {
+				expectLock(locker, "user123")
 
 				cart := testutils.CreateTestCart("user123", time.Hour)
 				item := testutils.CreateTestCartItem("prod1", "Product 1", 10.99, 2)
 				cart.AddItem(item, 10, 5)
-				
-				repo.On("GetCart", mock.Anything, "user123").Return(cart, nil)
+
+				repo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
 				inventoryClient.On("CheckAvailability", mock.Anything, "prod1", 1).Return(true, nil) // Additional quantity check
-				repo.On("SaveCart", mock.Anything, mock.AnythingOfType("*models.Cart")).Return(nil)
+				repo.On("SaveCartWithETag", mock.Anything, mock.AnythingOfType("*models.Cart"), "etag-1").Return(nil)
+				repo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -235,16 +426,16 @@ func TestCartService_UpdateItem(t *testing.T) {
 			request: models.UpdateItemRequest{
 				Quantity: 0,
 			},
-			setupMocks: func(repo *mocks.MockCartRepository, inventoryClient *mocks.MockInventoryClient) {
-				repo.On("AcquireLock", mock.Anything, "user123", 30*time.Second).Return(true, nil)
-				repo.On("ReleaseLock", mock.Anything, "user123").Return(nil)
+			setupMocks: func(repo *mocks.MockCartRepository, locker *mocks.MockLocker, inventoryClient *mocks.MockInventoryClient) {
+				expectLock(locker, "user123")
 
 				cart := testutils.CreateTestCart("user123", time.Hour)
 				item := testutils.CreateTestCartItem("prod1", "Product 1", 10.99, 2)
 				cart.AddItem(item, 10, 5)
-				
-				repo.On("GetCart", mock.Anything, "user123").Return(cart, nil)
-				repo.On("SaveCart", mock.Anything, mock.AnythingOfType("*models.Cart")).Return(nil)
+
+				repo.On("GetCartWithETag", mock.Anything, "user123").Return(cart, "etag-1", nil)
+				repo.On("SaveCartWithETag", mock.Anything, mock.AnythingOfType("*models.Cart"), "etag-1").Return(nil)
+				repo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -252,8 +443,8 @@ func TestCartService_UpdateItem(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cartService, mockRepo, _, mockInventoryClient := setupCartService()
-			tt.setupMocks(mockRepo, mockInventoryClient)
+			cartService, mockRepo, _, mockInventoryClient, mockLocker := setupCartService()
+			tt.setupMocks(mockRepo, mockLocker, mockInventoryClient)
 
 			cart, err := cartService.UpdateItem(context.Background(), tt.userID, tt.productID, tt.request)
 
@@ -263,7 +454,7 @@ func TestCartService_UpdateItem(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, cart)
-				
+
 				if tt.request.Quantity > 0 {
 					assert.True(t, cart.HasItem(tt.productID))
 					item, _ := cart.GetItem(tt.productID)
@@ -275,26 +466,27 @@ func TestCartService_UpdateItem(t *testing.T) {
 
 			mockRepo.AssertExpectations(t)
 			mockInventoryClient.AssertExpectations(t)
+			mockLocker.AssertExpectations(t)
 		})
 	}
 }
 
 func TestCartService_RemoveItem(t *testing.T) {
-	cartService, mockRepo, _, _ := setupCartService()
+	cartService, mockRepo, _, _, mockLocker := setupCartService()
 
 	userID := "user123"
 	productID := "prod1"
 
 	// Setup mocks
-	mockRepo.On("AcquireLock", mock.Anything, userID, 30*time.Second).Return(true, nil)
-	mockRepo.On("ReleaseLock", mock.Anything, userID).Return(nil)
+	expectLock(mockLocker, userID)
 
 	cart := testutils.CreateTestCart(userID, time.Hour)
 	item := testutils.CreateTestCartItem(productID, "Product 1", 10.99, 2)
 	cart.AddItem(item, 10, 5)
 
-	mockRepo.On("GetCart", mock.Anything, userID).Return(cart, nil)
-	mockRepo.On("SaveCart", mock.Anything, mock.AnythingOfType("*models.Cart")).Return(nil)
+	mockRepo.On("GetCartWithETag", mock.Anything, userID).Return(cart, "etag-1", nil)
+	mockRepo.On("SaveCartWithETag", mock.Anything, mock.AnythingOfType("*models.Cart"), "etag-1").Return(nil)
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
 
 	// Execute
 	result, err := cartService.RemoveItem(context.Background(), userID, productID)
@@ -305,16 +497,16 @@ func TestCartService_RemoveItem(t *testing.T) {
 	assert.False(t, result.HasItem(productID))
 
 	mockRepo.AssertExpectations(t)
+	mockLocker.AssertExpectations(t)
 }
 
 func TestCartService_ClearCart(t *testing.T) {
-	cartService, mockRepo, _, _ := setupCartService()
+	cartService, mockRepo, _, _, mockLocker := setupCartService()
 
 	userID := "user123"
 
 	// Setup mocks
-	mockRepo.On("AcquireLock", mock.Anything, userID, 30*time.Second).Return(true, nil)
-	mockRepo.On("ReleaseLock", mock.Anything, userID).Return(nil)
+	expectLock(mockLocker, userID)
 	mockRepo.On("DeleteCart", mock.Anything, userID).Return(nil)
 
 	// Execute
@@ -323,19 +515,23 @@ func TestCartService_ClearCart(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+	mockLocker.AssertExpectations(t)
 }
 
 func TestCartService_TransferCart(t *testing.T) {
-	cartService, mockRepo, _, _ := setupCartService()
+	cartService, mockRepo, _, mockInventoryClient, mockLocker := setupCartService()
 
 	fromUserID := "guest123"
 	toUserID := "user123"
 
 	// Setup mocks
-	mockRepo.On("AcquireLock", mock.Anything, fromUserID, 30*time.Second).Return(true, nil)
-	mockRepo.On("ReleaseLock", mock.Anything, fromUserID).Return(nil)
-	mockRepo.On("AcquireLock", mock.Anything, toUserID, 30*time.Second).Return(true, nil)
-	mockRepo.On("ReleaseLock", mock.Anything, toUserID).Return(nil)
+	expectLock(mockLocker, fromUserID)
+	expectLock(mockLocker, toUserID)
+	expectRepoToken(mockRepo, fromUserID, 1)
+	expectRepoToken(mockRepo, toUserID, 2)
+	mockRepo.On("RefreshLock", mock.Anything, fromUserID, int64(1), mock.Anything).Return(nil)
+	mockRepo.On("RefreshLock", mock.Anything, toUserID, int64(2), mock.Anything).Return(nil)
+	mockInventoryClient.On("CheckAvailability", mock.Anything, "prod1", 2).Return(true, nil)
 
 	// Create source cart with items
 	sourceCart := testutils.CreateTestCart(fromUserID, time.Hour)
@@ -347,8 +543,9 @@ func TestCartService_TransferCart(t *testing.T) {
 
 	mockRepo.On("GetCart", mock.Anything, fromUserID).Return(sourceCart, nil)
 	mockRepo.On("GetCart", mock.Anything, toUserID).Return(targetCart, nil)
-	mockRepo.On("SaveCart", mock.Anything, mock.AnythingOfType("*models.Cart")).Return(nil)
-	mockRepo.On("DeleteCart", mock.Anything, fromUserID).Return(nil)
+	mockRepo.On("SaveCartWithToken", mock.Anything, mock.AnythingOfType("*models.Cart"), int64(2)).Return(nil)
+	mockRepo.On("SaveCartWithOutbox", mock.Anything, mock.AnythingOfType("*models.Cart"), mock.AnythingOfType("[]models.OutboxRecord")).Return(nil)
+	mockRepo.On("DeleteCartWithToken", mock.Anything, fromUserID, int64(1)).Return(nil)
 
 	// Execute
 	result, err := cartService.TransferCart(context.Background(), fromUserID, toUserID)
@@ -360,4 +557,5 @@ func TestCartService_TransferCart(t *testing.T) {
 	assert.True(t, result.HasItem("prod1"))
 
 	mockRepo.AssertExpectations(t)
+	mockLocker.AssertExpectations(t)
 }
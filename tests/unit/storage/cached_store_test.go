@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aioutlet/cart-service/internal/models"
+	"github.com/aioutlet/cart-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeStore is an in-memory storage.Store used to exercise CachedStore's
+// write-through/read-through behavior without a real Postgres/Redis.
+type fakeStore struct {
+	carts map[string]*models.Cart
+	gets  int
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{carts: make(map[string]*models.Cart)}
+}
+
+func (f *fakeStore) Get(ctx context.Context, userID string) (*models.Cart, error) {
+	f.gets++
+	cart, ok := f.carts[userID]
+	if !ok {
+		return nil, models.ErrCartNotFound
+	}
+	return cart, nil
+}
+
+func (f *fakeStore) Save(ctx context.Context, cart *models.Cart) error {
+	f.carts[cart.UserID] = cart
+	return nil
+}
+
+func (f *fakeStore) Delete(ctx context.Context, userID string) error {
+	delete(f.carts, userID)
+	return nil
+}
+
+func (f *fakeStore) ListExpired(ctx context.Context, limit int) ([]*models.Cart, error) {
+	var expired []*models.Cart
+	for _, cart := range f.carts {
+		if cart.IsExpired() {
+			expired = append(expired, cart)
+		}
+		if len(expired) >= limit {
+			break
+		}
+	}
+	return expired, nil
+}
+
+func TestCachedStore_GetRepopulatesCache(t *testing.T) {
+	primary := newFakeStore()
+	cache := newFakeStore()
+	store := storage.NewCachedStore(primary, cache, zap.NewNop())
+
+	cart := models.NewCart("user123", time.Hour)
+	primary.carts["user123"] = cart
+
+	got, err := store.Get(context.Background(), "user123")
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", got.UserID)
+
+	_, ok := cache.carts["user123"]
+	assert.True(t, ok, "cache should be repopulated on primary read")
+}
+
+func TestCachedStore_SaveIsWriteThrough(t *testing.T) {
+	primary := newFakeStore()
+	cache := newFakeStore()
+	store := storage.NewCachedStore(primary, cache, zap.NewNop())
+
+	cart := models.NewCart("user123", time.Hour)
+	assert.NoError(t, store.Save(context.Background(), cart))
+
+	_, inPrimary := primary.carts["user123"]
+	_, inCache := cache.carts["user123"]
+	assert.True(t, inPrimary)
+	assert.True(t, inCache)
+}
+
+func TestReaper_SweepDeletesExpiredCarts(t *testing.T) {
+	primary := newFakeStore()
+	cache := newFakeStore()
+	store := storage.NewCachedStore(primary, cache, zap.NewNop())
+
+	expired := models.NewCart("expired-user", -time.Hour)
+	live := models.NewCart("live-user", time.Hour)
+	primary.carts["expired-user"] = expired
+	primary.carts["live-user"] = live
+	cache.carts["expired-user"] = expired
+	cache.carts["live-user"] = live
+
+	reaper := storage.NewReaper(store, time.Minute, 10, zap.NewNop(), nil)
+	reaper.Sweep(context.Background())
+
+	assert.Contains(t, primary.carts, "live-user")
+	assert.NotContains(t, primary.carts, "expired-user")
+}
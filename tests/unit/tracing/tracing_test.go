@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aioutlet/cart-service/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+)
+
+func TestInitTracing_Disabled(t *testing.T) {
+	tp, err := tracing.InitTracing(tracing.TracingConfig{Enabled: false}, zap.NewNop())
+
+	assert.NoError(t, err)
+	assert.NotNil(t, tp)
+}
+
+func TestInitTracing_None(t *testing.T) {
+	tp, err := tracing.InitTracing(tracing.TracingConfig{
+		Enabled:      true,
+		ExporterType: tracing.ExporterNone,
+	}, zap.NewNop())
+
+	assert.NoError(t, err)
+	assert.NotNil(t, tp)
+}
+
+func TestInitTracing_Stdout_SamplingAndShutdown(t *testing.T) {
+	cfg := tracing.TracingConfig{
+		Enabled:        true,
+		ServiceName:    "cart-service",
+		ServiceVersion: "test",
+		Environment:    "test",
+		ExporterType:   tracing.ExporterStdout,
+		SampleRate:     0,
+	}
+
+	tp, err := tracing.InitTracing(cfg, zap.NewNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, tp)
+
+	otel.SetTracerProvider(tp)
+
+	_, span := tracing.GetTracer().Start(context.Background(), "test-span")
+	span.End()
+
+	assert.False(t, span.SpanContext().IsSampled(), "sample rate of 0 should not record spans")
+
+	tracing.Shutdown(context.Background(), tp, zap.NewNop())
+
+	// GetTracer must keep returning the "cart-service" tracer after shutdown.
+	assert.NotNil(t, tracing.GetTracer())
+}
+
+func TestWithCartSpan_ExpectedErrorIsStillReturned(t *testing.T) {
+	errMaxQty := errors.New("maximum quantity per item exceeded")
+
+	err := tracing.WithCartSpan(context.Background(), "add_item", "user123", []error{errMaxQty}, func(ctx context.Context) error {
+		tracing.SetCartTotalItems(ctx, 3)
+		return errMaxQty
+	})
+
+	assert.ErrorIs(t, err, errMaxQty)
+}
+
+func TestWithCartSpan_UnexpectedErrorIsReturned(t *testing.T) {
+	unexpected := errors.New("boom")
+
+	err := tracing.WithCartSpan(context.Background(), "add_item", "user123", nil, func(ctx context.Context) error {
+		return unexpected
+	})
+
+	assert.ErrorIs(t, err, unexpected)
+}
+
+func TestDynamicSampler_UpdateChangesRatio(t *testing.T) {
+	sampler := tracing.NewDynamicSampler(0)
+
+	tp, err := tracing.InitTracing(tracing.TracingConfig{
+		Enabled:      true,
+		ExporterType: tracing.ExporterStdout,
+		Sampler:      sampler,
+	}, zap.NewNop())
+	assert.NoError(t, err)
+	otel.SetTracerProvider(tp)
+
+	_, span := tracing.GetTracer().Start(context.Background(), "unsampled")
+	span.End()
+	assert.False(t, span.SpanContext().IsSampled(), "ratio of 0 should not sample")
+
+	sampler.Update(1)
+
+	_, span = tracing.GetTracer().Start(context.Background(), "sampled")
+	span.End()
+	assert.True(t, span.SpanContext().IsSampled(), "ratio of 1 should always sample")
+
+	tracing.Shutdown(context.Background(), tp, zap.NewNop())
+}